@@ -1,8 +1,14 @@
 package sleepiq
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
 )
 
 // ============================================================================
@@ -31,11 +37,34 @@ type InsightsLoginResponse struct {
 	Error     ServiceError `json:"Error"`
 }
 
+// CredentialsProvider supplies the username and password used to login again
+// when the session of a long-running client expires
+type CredentialsProvider func(ctx context.Context) (username string, password string, err error)
+
+// authState holds the session details of a SleepIQ instance. The state is
+// guarded by a mutex as it is read by every request and updated by logins
+// and cookie refreshes that may happen concurrently.
+type authState struct {
+	mu                 sync.RWMutex
+	isLoggedIn         bool
+	isInsightsLoggedIn bool
+	loginKey           string
+	insightsToken      string
+	cookies            []*http.Cookie
+	loginTime          time.Time
+	username           string
+	password           string
+}
+
 // Login authenticates the user against the SleepIQ service
 func (s *SleepIQ) Login(username string, password string) (LoginResponse, error) {
+	return s.LoginContext(context.Background(), username, password)
+}
+
+// LoginContext is like Login but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) LoginContext(ctx context.Context, username string, password string) (LoginResponse, error) {
 	var response LoginResponse
-	s.isLoggedIn = false
-	s.loginKey = ""
 
 	// Create the loginCredentials object
 	creds := loginCredentials{
@@ -45,30 +74,27 @@ func (s *SleepIQ) Login(username string, password string) (LoginResponse, error)
 
 	credBytes, err := json.Marshal(creds)
 	if err != nil {
-		return response, fmt.Errorf("login could not execute - %s", err)
+		s.auth.clearLogin()
+		return response, fmt.Errorf("login could not execute - %w", err)
 	}
 
 	// Login request
-	responseBytes, cookies, err := httpPut("https://prod-api.sleepiq.sleepnumber.com/rest/login", credBytes, s.cookies)
+	responseBytes, cookies, err := s.httpPut(ctx, s.baseURL+"/login", credBytes)
 	if err != nil {
-		return response, fmt.Errorf("login failed - %s", err)
+		s.auth.clearLogin()
+		response.Error = loginServiceError(err)
+		return response, fmt.Errorf("login failed - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read login response - %s", err)
-	}
-
-	// Check if the response contained an error
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("Login failed - Error #%d: %s", response.Error.Code, response.Error.Message)
+		s.auth.clearLogin()
+		return response, fmt.Errorf("could not read login response - %w", err)
 	}
 
 	// Update the sleepiq object
-	s.loginKey = response.Key
-	s.isLoggedIn = true
-	s.cookies = cookies
+	s.auth.setLogin(response.Key, cookies, username, password)
 
 	return response, nil
 }
@@ -81,9 +107,13 @@ func (s *SleepIQ) Login(username string, password string) (LoginResponse, error)
 // service. This is a separate service that provides additional
 // analysis on a sleeper's sleep behavior
 func (s *SleepIQ) InsightsLogin(username string, password string) (InsightsLoginResponse, error) {
+	return s.InsightsLoginContext(context.Background(), username, password)
+}
+
+// InsightsLoginContext is like InsightsLogin but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) InsightsLoginContext(ctx context.Context, username string, password string) (InsightsLoginResponse, error) {
 	var response InsightsLoginResponse
-	s.isInsightsLoggedIn = false
-	s.insightsToken = ""
 
 	// Create the loginCredentials object
 	creds := loginCredentials{
@@ -93,29 +123,225 @@ func (s *SleepIQ) InsightsLogin(username string, password string) (InsightsLogin
 
 	credBytes, err := json.Marshal(creds)
 	if err != nil {
-		return response, fmt.Errorf("login could not execute - %s", err)
+		s.auth.clearInsightsLogin()
+		return response, fmt.Errorf("login could not execute - %w", err)
 	}
 
 	// Login request
-	responseBytes, err := httpPost("https://sleepiqapi.azure-api.net/prod/accesstoken", credBytes)
+	responseBytes, err := s.httpPost(ctx, s.insightsBaseURL+"/accesstoken", credBytes)
 	if err != nil {
-		return response, fmt.Errorf("login failed - %s", err)
+		s.auth.clearInsightsLogin()
+		response.Error = loginServiceError(err)
+		return response, fmt.Errorf("login failed - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read login response - %s", err)
-	}
-
-	// Check if the response contained an error
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("Login failed - Error #%d: %s", response.Error.Code, response.Error.Message)
+		s.auth.clearInsightsLogin()
+		return response, fmt.Errorf("could not read login response - %w", err)
 	}
 
 	// Update the sleepiq object
-	s.insightsToken = response.Token
-	s.isInsightsLoggedIn = true
+	s.auth.setInsightsLogin(response.Token, username, password)
 
 	return response, nil
 }
+
+// ============================================================================
+// SUPPORTING FUNCTIONS
+// ============================================================================
+
+// loginServiceError extracts the service error from a failed login request.
+// A rejected login is reported as ErrInvalidCredentials rather than as an
+// expired session.
+func loginServiceError(err error) ServiceError {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return ServiceError{}
+	}
+
+	if apiErr.StatusCode == http.StatusUnauthorized || apiErr.Code == http.StatusUnauthorized {
+		apiErr.err = ErrInvalidCredentials
+	}
+
+	return apiErr.ServiceError
+}
+
+// relogin authenticates again against the service that issued the session used by the
+// provided url. The url is returned with the session key or token of the new session.
+// Concurrent requests that fail with the same expired session share a single login.
+func (s *SleepIQ) relogin(ctx context.Context, rawURL string) (string, error) {
+	requestURL, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, err
+	}
+
+	// Login requests carry no session and are never retried with a new one. Checking
+	// before taking the lock also keeps a rejected login from waiting on the relogin
+	// that sent it.
+	query := requestURL.Query()
+	key, token := query.Get("_k"), query.Get("access_token")
+	if key == "" && token == "" {
+		return rawURL, errors.New("request is not associated with a session")
+	}
+
+	s.reloginMu.Lock()
+	defer s.reloginMu.Unlock()
+
+	if key != "" {
+		// Only login if no other request renewed the session in the meantime
+		if key == s.auth.key() {
+			err = s.reloginWith(ctx, func(username string, password string) error {
+				_, err := s.LoginContext(ctx, username, password)
+				return err
+			})
+		}
+		query.Set("_k", s.auth.key())
+	} else {
+		if token == s.auth.token() {
+			err = s.reloginWith(ctx, func(username string, password string) error {
+				_, err := s.InsightsLoginContext(ctx, username, password)
+				return err
+			})
+		}
+		query.Set("access_token", s.auth.token())
+	}
+
+	if err != nil {
+		return rawURL, err
+	}
+
+	requestURL.RawQuery = query.Encode()
+	return requestURL.String(), nil
+}
+
+// reloginWith calls the login function with the credentials from the credentials
+// provider or, if there is no provider, from the most recent login
+func (s *SleepIQ) reloginWith(ctx context.Context, login func(username string, password string) error) error {
+	var username, password string
+	if s.credentials != nil {
+		var err error
+		username, password, err = s.credentials(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		username, password = s.auth.loginCredentials()
+	}
+
+	if username == "" {
+		return errors.New("no credentials are available to login again")
+	}
+
+	return login(username, password)
+}
+
+// ============================================================================
+// SESSION STATE
+// ============================================================================
+
+// loggedIn reports whether there is an active session with the SleepIQ service
+func (a *authState) loggedIn() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.isLoggedIn
+}
+
+// insightsLoggedIn reports whether there is an active session with the Insights service
+func (a *authState) insightsLoggedIn() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.isInsightsLoggedIn
+}
+
+// key returns the login key of the SleepIQ session
+func (a *authState) key() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.loginKey
+}
+
+// token returns the access token of the Insights session
+func (a *authState) token() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.insightsToken
+}
+
+// sessionCookies returns the cookies of the SleepIQ session
+func (a *authState) sessionCookies() []*http.Cookie {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cookies
+}
+
+// loginCredentials returns the credentials of the most recent login
+func (a *authState) loginCredentials() (string, string) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.username, a.password
+}
+
+// setLogin stores the details of a successful login to the SleepIQ service
+func (a *authState) setLogin(key string, cookies []*http.Cookie, username string, password string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.loginKey = key
+	a.isLoggedIn = true
+	a.cookies = cookies
+	a.loginTime = time.Now()
+	a.username = username
+	a.password = password
+}
+
+// setInsightsLogin stores the details of a successful login to the Insights service
+func (a *authState) setInsightsLogin(token string, username string, password string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.insightsToken = token
+	a.isInsightsLoggedIn = true
+	a.loginTime = time.Now()
+	a.username = username
+	a.password = password
+}
+
+// clearLogin removes the SleepIQ session after a failed login
+func (a *authState) clearLogin() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.loginKey = ""
+	a.isLoggedIn = false
+}
+
+// clearInsightsLogin removes the Insights session after a failed login
+func (a *authState) clearInsightsLogin() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.insightsToken = ""
+	a.isInsightsLoggedIn = false
+}
+
+// updateCookies replaces the session cookies that were refreshed by the service
+func (a *authState) updateCookies(cookies []*http.Cookie) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	updated := make([]*http.Cookie, 0, len(a.cookies)+len(cookies))
+	for _, cookie := range a.cookies {
+		if !containsCookie(cookies, cookie.Name) {
+			updated = append(updated, cookie)
+		}
+	}
+	a.cookies = append(updated, cookies...)
+}
+
+// containsCookie reports whether a cookie with the given name is in the list
+func containsCookie(cookies []*http.Cookie, name string) bool {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return true
+		}
+	}
+	return false
+}
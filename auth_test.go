@@ -1,13 +1,23 @@
 package sleepiq
 
 import (
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
 )
 
 func TestLoginSuccess(t *testing.T) {
-	sleepiq := New()
-	response, err := sleepiq.Login(os.Getenv("sleepiq_username"), os.Getenv("sleepiq_password"))
+	server, sleepiq := newTestServer()
+	defer server.Close()
+
+	response, err := sleepiq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
 	if err != nil {
 		t.Error("Login failed - expected success", err)
 		return
@@ -20,7 +30,9 @@ func TestLoginSuccess(t *testing.T) {
 }
 
 func TestLoginBadCredentials(t *testing.T) {
-	sleepiq := New()
+	server, sleepiq := newTestServer()
+	defer server.Close()
+
 	response, err := sleepiq.Login("JohnDoe@live.com", "bogusPassword")
 	if err == nil {
 		t.Error("Login succeeded - expected failure", err)
@@ -34,8 +46,10 @@ func TestLoginBadCredentials(t *testing.T) {
 }
 
 func TestInsightsLoginSuccess(t *testing.T) {
-	sleepiq := New()
-	response, err := sleepiq.InsightsLogin(os.Getenv("sleepiq_username"), os.Getenv("sleepiq_password"))
+	server, sleepiq := newTestServer()
+	defer server.Close()
+
+	response, err := sleepiq.InsightsLogin(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
 	if err != nil {
 		t.Error("Login failed - expected success", err)
 		return
@@ -46,3 +60,175 @@ func TestInsightsLoginSuccess(t *testing.T) {
 		return
 	}
 }
+
+func TestLoginBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/login" {
+			t.Errorf("unexpected request path. Expected=%s, Actual=%s", "/rest/login", r.URL.Path)
+		}
+		w.Write([]byte(`{"userId":"1","key":"testKey"}`))
+	}))
+	defer server.Close()
+
+	sleepiq := New(WithBaseURL(server.URL + "/rest/"))
+	response, err := sleepiq.Login("JohnDoe@live.com", "password")
+	if err != nil {
+		t.Error("Login failed - expected success", err)
+		return
+	}
+
+	if response.Key != "testKey" {
+		t.Errorf("Login failed - unexpected key. Expected=%s, Actual=%s", "testKey", response.Key)
+	}
+}
+
+func TestReloginOnExpiredSession(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			logins++
+			w.Write([]byte(fmt.Sprintf(`{"userId":"1","key":"key%d"}`, logins)))
+		case "/bed":
+			if r.URL.Query().Get("_k") != "key2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"Error":{"Code":50002,"Message":"Session is invalid"}}`))
+				return
+			}
+			w.Write([]byte(`{"beds":[{"bedId":"1"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	siq := New(WithBaseURL(server.URL))
+	_, err := siq.Login("JohnDoe@live.com", "password")
+	if err != nil {
+		t.Error("login failed - expected success", err)
+		return
+	}
+
+	beds, err := siq.Beds()
+	if err != nil {
+		t.Errorf("could not get beds - %s", err)
+		return
+	}
+
+	if len(beds.Beds) != 1 {
+		t.Errorf("unexpected number of beds. Expected=%d, Actual=%d", 1, len(beds.Beds))
+	}
+
+	if logins != 2 {
+		t.Errorf("session was not renewed. Expected logins=%d, Actual=%d", 2, logins)
+	}
+}
+
+func TestReloginCredentialsProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"Error":{"Code":50002,"Message":"Session is invalid"}}`))
+	}))
+	defer server.Close()
+
+	requested := false
+	siq := New(WithBaseURL(server.URL), WithCredentials(func(ctx context.Context) (string, string, error) {
+		requested = true
+		return "", "", errors.New("no credentials")
+	}))
+	siq.auth.isLoggedIn = true
+	siq.auth.loginKey = "expired"
+
+	_, err := siq.Beds()
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired. Actual=%v", err)
+	}
+
+	if !requested {
+		t.Error("credentials provider was not used")
+	}
+}
+
+func TestReloginConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/login":
+			logins++
+			w.Write([]byte(fmt.Sprintf(`{"userId":"1","key":"key%d"}`, logins)))
+		case "/bed":
+			if r.URL.Query().Get("_k") != fmt.Sprintf("key%d", logins) || logins == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"beds":[{"bedId":"1"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	siq := New(WithBaseURL(server.URL))
+	_, err := siq.Login("JohnDoe@live.com", "password")
+	if err != nil {
+		t.Error("login failed - expected success", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := siq.Beds()
+			if err != nil {
+				t.Errorf("could not get beds - %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logins != 2 {
+		t.Errorf("expired session was renewed more than once. Expected logins=%d, Actual=%d", 2, logins)
+	}
+}
+
+func TestReloginRejected(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	_, err = siq.InsightsLogin(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("insights login failed - expected success. %s", err)
+	}
+
+	// The password was changed so the session cannot be renewed
+	server.SetCredentials(sleepiqtest.DefaultUsername, "changed")
+	server.ExpireSession()
+
+	done := make(chan error, 2)
+	go func() {
+		_, err := siq.Beds()
+		done <- err
+	}()
+	go func() {
+		_, err := siq.InsightsProviders()
+		done <- err
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-done:
+			if !errors.Is(err, ErrSessionExpired) {
+				t.Errorf("expected ErrSessionExpired. Actual=%v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("request did not return after the login was rejected")
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package sleepiq
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,12 +17,6 @@ const (
 	BedTypeEasternKing = 3
 )
 
-// Sides of the bed
-const (
-	BedSideLeft  = 0
-	BedSideRight = 1
-)
-
 // ============================================================================
 // BEDS
 // ============================================================================
@@ -60,31 +54,32 @@ type Bed struct {
 }
 
 // Beds returns properties about all beds associated with the account
-func (s SleepIQ) Beds() (BedsInfo, error) {
+func (s *SleepIQ) Beds() (BedsInfo, error) {
+	return s.BedsContext(context.Background())
+}
+
+// BedsContext is like Beds but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedsContext(ctx context.Context) (BedsInfo, error) {
 	var response BedsInfo
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed?_k={{key}}", "{{key}}", s.auth.key(), -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed details - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed details - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read beds response - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read beds response - %w", err)
 	}
 
 	return response, nil
@@ -102,34 +97,41 @@ type BedPrivacyModeDetails struct {
 	Error     ServiceError `json:"Error"`
 }
 
+// Enabled reports whether privacy mode is on, in which case the bed does not
+// collect sleep data
+func (p BedPrivacyModeDetails) Enabled() bool {
+	return strings.ToLower(p.PauseMode) == "on"
+}
+
 // BedPrivacyMode gets the privacy mode for the specified bed. The bedID can be obtained via the call
 // to Beds().
-func (s SleepIQ) BedPrivacyMode(bedID string) (BedPrivacyModeDetails, error) {
+func (s *SleepIQ) BedPrivacyMode(bedID string) (BedPrivacyModeDetails, error) {
+	return s.BedPrivacyModeContext(context.Background(), bedID)
+}
+
+// BedPrivacyModeContext is like BedPrivacyMode but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedPrivacyModeContext(ctx context.Context, bedID string) (BedPrivacyModeDetails, error) {
 	var response BedPrivacyModeDetails
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/pauseMode?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/pauseMode?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed pause mode - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed pause mode - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed pause mode - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed pause mode - %w", err)
 	}
 
 	return response, nil
@@ -141,55 +143,105 @@ func (s SleepIQ) BedPrivacyMode(bedID string) (BedPrivacyModeDetails, error) {
 
 // FamilyStatusDetails describes the bed settings for each bed
 type FamilyStatusDetails struct {
-	Beds []struct {
-		Status   int    `json:"status"`
-		BedID    string `json:"bedId"`
-		LeftSide struct {
-			IsInBed              bool   `json:"isInBed"`
-			AlertDetailedMessage string `json:"alertDetailedMessage"`
-			SleepNumber          int    `json:"sleepNumber"`
-			AlertID              int    `json:"alertId"`
-			LastLink             string `json:"lastLink"`
-			Pressure             int    `json:"pressure"`
-		} `json:"leftSide"`
-		RightSide struct {
-			IsInBed              bool   `json:"isInBed"`
-			AlertDetailedMessage string `json:"alertDetailedMessage"`
-			SleepNumber          int    `json:"sleepNumber"`
-			AlertID              int    `json:"alertId"`
-			LastLink             string `json:"lastLink"`
-			Pressure             int    `json:"pressure"`
-		} `json:"rightSide"`
-	} `json:"beds"`
+	Beds  []BedStatus  `json:"beds"`
 	Error ServiceError `json:"Error"`
 }
 
+// Bed returns the status of the bed with the given ID
+func (f FamilyStatusDetails) Bed(bedID string) (BedStatus, bool) {
+	for _, bed := range f.Beds {
+		if bed.BedID == bedID {
+			return bed, true
+		}
+	}
+	return BedStatus{}, false
+}
+
+// BedStatus describes the occupancy and sleep number of both sides of a bed
+type BedStatus struct {
+	Status int
+	BedID  string
+	Sides  [2]SideStatus // Status of each side, indexed by Side
+}
+
+// bedStatusJSON is the form of BedStatus that is used by the service
+type bedStatusJSON struct {
+	Status    int        `json:"status"`
+	BedID     string     `json:"bedId"`
+	LeftSide  SideStatus `json:"leftSide"`
+	RightSide SideStatus `json:"rightSide"`
+}
+
+// Side returns the status of one side of the bed
+func (b BedStatus) Side(side Side) SideStatus {
+	if !side.valid() {
+		return SideStatus{}
+	}
+	return b.Sides[side]
+}
+
+// MarshalJSON writes the bed status in the form used by the service
+func (b BedStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bedStatusJSON{
+		Status:    b.Status,
+		BedID:     b.BedID,
+		LeftSide:  b.Sides[Left],
+		RightSide: b.Sides[Right],
+	})
+}
+
+// UnmarshalJSON reads the bed status in the form used by the service
+func (b *BedStatus) UnmarshalJSON(data []byte) error {
+	var status bedStatusJSON
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+
+	*b = BedStatus{
+		Status: status.Status,
+		BedID:  status.BedID,
+		Sides:  [2]SideStatus{Left: status.LeftSide, Right: status.RightSide},
+	}
+	return nil
+}
+
+// SideStatus describes the occupancy and sleep number of one side of a bed
+type SideStatus struct {
+	IsInBed              bool   `json:"isInBed"`
+	AlertDetailedMessage string `json:"alertDetailedMessage"`
+	SleepNumber          int    `json:"sleepNumber"`
+	AlertID              int    `json:"alertId"`
+	LastLink             string `json:"lastLink"`
+	Pressure             int    `json:"pressure"`
+}
+
 // BedFamilyStatus gets the settings for each bed as well as each side of a bed (if applicable)
-func (s SleepIQ) BedFamilyStatus() (FamilyStatusDetails, error) {
+func (s *SleepIQ) BedFamilyStatus() (FamilyStatusDetails, error) {
+	return s.BedFamilyStatusContext(context.Background())
+}
+
+// BedFamilyStatusContext is like BedFamilyStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedFamilyStatusContext(ctx context.Context) (FamilyStatusDetails, error) {
 	var response FamilyStatusDetails
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/familyStatus?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/familyStatus?_k={{key}}", "{{key}}", s.auth.key(), -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed family status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed family status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed family status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed family status - %w", err)
 	}
 
 	return response, nil
@@ -237,32 +289,33 @@ type BedDetailedInfo struct {
 }
 
 // BedDetailedStatus gets the settings for each bed as well as each side of a bed (if applicable)
-func (s SleepIQ) BedDetailedStatus(bedID string) (BedDetailedInfo, error) {
+func (s *SleepIQ) BedDetailedStatus(bedID string) (BedDetailedInfo, error) {
+	return s.BedDetailedStatusContext(context.Background(), bedID)
+}
+
+// BedDetailedStatusContext is like BedDetailedStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedDetailedStatusContext(ctx context.Context, bedID string) (BedDetailedInfo, error) {
 	var response BedDetailedInfo
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/superStatus?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/superStatus?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed detailed status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed detailed status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed detailed status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed detailed status - %w", err)
 	}
 
 	return response, nil
@@ -280,32 +333,33 @@ type BedNodesDetails struct {
 }
 
 // BedNodes gets the nodes for the provided bed
-func (s SleepIQ) BedNodes(bedID string) (BedNodesDetails, error) {
+func (s *SleepIQ) BedNodes(bedID string) (BedNodesDetails, error) {
+	return s.BedNodesContext(context.Background(), bedID)
+}
+
+// BedNodesContext is like BedNodes but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedNodesContext(ctx context.Context, bedID string) (BedNodesDetails, error) {
 	var response BedNodesDetails
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/nodes?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/nodes?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed nodes - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed nodes - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed nodes - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed nodes - %w", err)
 	}
 
 	return response, nil
@@ -328,32 +382,77 @@ type ResponsiveAirSettings struct {
 }
 
 // BedResponsiveAir gets the responsive air settings for the provided bed
-func (s SleepIQ) BedResponsiveAir(bedID string) (ResponsiveAirSettings, error) {
+func (s *SleepIQ) BedResponsiveAir(bedID string) (ResponsiveAirSettings, error) {
+	return s.BedResponsiveAirContext(context.Background(), bedID)
+}
+
+// BedResponsiveAirContext is like BedResponsiveAir but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedResponsiveAirContext(ctx context.Context, bedID string) (ResponsiveAirSettings, error) {
 	var response ResponsiveAirSettings
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/responsiveAir?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/responsiveAir?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed responsive error settings - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed responsive error settings - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed responsive error settings - %s", err)
+		return response, fmt.Errorf("could not read bed responsive error settings - %w", err)
+	}
+
+	return response, nil
+}
+
+// ============================================================================
+// SLEEP NUMBER FAVORITE
+// ============================================================================
+
+// SleepNumberFavorite describes the favorite sleep number of each side of the bed
+type SleepNumberFavorite struct {
+	SleepNumberFavoriteLeft  int          `json:"sleepNumberFavoriteLeft"`
+	SleepNumberFavoriteRight int          `json:"sleepNumberFavoriteRight"`
+	Error                    ServiceError `json:"Error"`
+}
+
+// BedSleepNumberFavorite gets the favorite sleep numbers for the provided bed
+func (s *SleepIQ) BedSleepNumberFavorite(bedID string) (SleepNumberFavorite, error) {
+	return s.BedSleepNumberFavoriteContext(context.Background(), bedID)
+}
+
+// BedSleepNumberFavoriteContext is like BedSleepNumberFavorite but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedSleepNumberFavoriteContext(ctx context.Context, bedID string) (SleepNumberFavorite, error) {
+	var response SleepNumberFavorite
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
+	}
+
+	// Make request
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/sleepNumberFavorite?_k={{key}}", "{{key}}", s.auth.key(), -1)
+	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
+	if err != nil {
+		return response, fmt.Errorf("unable to retrieve sleep number favorite - %w", err)
 	}
 
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+	// Marshal the response to a loginResponse object
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return response, fmt.Errorf("could not read sleep number favorite - %w", err)
 	}
 
 	return response, nil
@@ -372,33 +471,61 @@ type FootWarmingStatus struct {
 	Error                  ServiceError `json:"Error"`
 }
 
+// FootWarmerState is the decoded form of FootWarmingStatus
+type FootWarmerState struct {
+	Left  FootWarmerSide
+	Right FootWarmerSide
+}
+
+// FootWarmerSide is the decoded foot warmer state of one side of the bed
+type FootWarmerSide struct {
+	Level     FootWarmerLevel
+	Remaining time.Duration // Time left before the foot warmer turns off
+}
+
+// Decode converts the raw foot warmer status into typed levels and the time
+// left on each side
+func (f FootWarmingStatus) Decode() FootWarmerState {
+	return FootWarmerState{
+		Left: FootWarmerSide{
+			Level:     FootWarmerLevel(f.FootWarmingStatusLeft),
+			Remaining: time.Duration(f.FootWarmingTimerLeft) * time.Minute,
+		},
+		Right: FootWarmerSide{
+			Level:     FootWarmerLevel(f.FootWarmingStatusRight),
+			Remaining: time.Duration(f.FootWarmingTimerRight) * time.Minute,
+		},
+	}
+}
+
 // BedFootWarmerStatus retrieves the foot warmer status for the bed
-func (s SleepIQ) BedFootWarmerStatus(bedID string) (FootWarmingStatus, error) {
+func (s *SleepIQ) BedFootWarmerStatus(bedID string) (FootWarmingStatus, error) {
+	return s.BedFootWarmerStatusContext(context.Background(), bedID)
+}
+
+// BedFootWarmerStatusContext is like BedFootWarmerStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedFootWarmerStatusContext(ctx context.Context, bedID string) (FootWarmingStatus, error) {
 	var response FootWarmingStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/footwarming?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/footwarming?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed foot warmer status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed foot warmer status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed foot warmer status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed foot warmer status - %w", err)
 	}
 
 	return response, nil
@@ -421,32 +548,33 @@ type BedSystemStatus struct {
 }
 
 // BedSystemStatus retrieves the board and lighting status of the bed
-func (s SleepIQ) BedSystemStatus(bedID string) (BedSystemStatus, error) {
+func (s *SleepIQ) BedSystemStatus(bedID string) (BedSystemStatus, error) {
+	return s.BedSystemStatusContext(context.Background(), bedID)
+}
+
+// BedSystemStatusContext is like BedSystemStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedSystemStatusContext(ctx context.Context, bedID string) (BedSystemStatus, error) {
 	var response BedSystemStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/system?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/system?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed system status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed system status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed system status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed system status - %w", err)
 	}
 
 	return response, nil
@@ -474,32 +602,33 @@ type BedPinchStatus struct {
 }
 
 // BedPinchStatus retrieves the pinch status of the bed
-func (s SleepIQ) BedPinchStatus(bedID string) (BedPinchStatus, error) {
+func (s *SleepIQ) BedPinchStatus(bedID string) (BedPinchStatus, error) {
+	return s.BedPinchStatusContext(context.Background(), bedID)
+}
+
+// BedPinchStatusContext is like BedPinchStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedPinchStatusContext(ctx context.Context, bedID string) (BedPinchStatus, error) {
 	var response BedPinchStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/pinch?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/pinch?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed pinch status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed pinch status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed pinch status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed pinch status - %w", err)
 	}
 
 	return response, nil
@@ -517,32 +646,33 @@ type UnderbedLightStatus struct {
 }
 
 // BedLightStatus retrieves the status of the underbed light
-func (s SleepIQ) BedLightStatus(bedID string) (UnderbedLightStatus, error) {
+func (s *SleepIQ) BedLightStatus(bedID string) (UnderbedLightStatus, error) {
+	return s.BedLightStatusContext(context.Background(), bedID)
+}
+
+// BedLightStatusContext is like BedLightStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedLightStatusContext(ctx context.Context, bedID string) (UnderbedLightStatus, error) {
 	var response UnderbedLightStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/underbedLight?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/underbedLight?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed light status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed light status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed light status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed light status - %w", err)
 	}
 
 	return response, nil
@@ -584,37 +714,50 @@ type BedFoundationStatus struct {
 }
 
 // BedFoundationStatus retrieves the status of the bed foundation
-func (s SleepIQ) BedFoundationStatus(bedID string) (BedFoundationStatus, error) {
+func (s *SleepIQ) BedFoundationStatus(bedID string) (BedFoundationStatus, error) {
+	return s.BedFoundationStatusContext(context.Background(), bedID)
+}
+
+// BedFoundationStatusContext is like BedFoundationStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedFoundationStatusContext(ctx context.Context, bedID string) (BedFoundationStatus, error) {
 	var response BedFoundationStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/status?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/status?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed foundation status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed foundation status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed foundation status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed foundation status - %w", err)
 	}
 
 	return response, nil
 }
 
+// LeftPositionTimer returns the time left before the left side of the bed
+// returns to flat from a timed preset. It is zero when no timer is running.
+func (f BedFoundationStatus) LeftPositionTimer() (time.Duration, error) {
+	return positionTimer(f.LeftPositionTimerMSB, f.LeftPositionTimerLSB)
+}
+
+// RightPositionTimer returns the time left before the right side of the bed
+// returns to flat from a timed preset. It is zero when no timer is running.
+func (f BedFoundationStatus) RightPositionTimer() (time.Duration, error) {
+	return positionTimer(f.RightPositionTimerMSB, f.RightPositionTimerLSB)
+}
+
 // ============================================================================
 // UNDERBED LIGHT OUTLET STATUS
 // ============================================================================
@@ -629,33 +772,34 @@ type UnderbedLightOutletStatus struct {
 }
 
 // BedLightingOutletStatus retrieves the status of the underbed lighting outlet
-func (s SleepIQ) BedLightingOutletStatus(bedID string, outletID int) (UnderbedLightOutletStatus, error) {
+func (s *SleepIQ) BedLightingOutletStatus(bedID string, outletID int) (UnderbedLightOutletStatus, error) {
+	return s.BedLightingOutletStatusContext(context.Background(), bedID, outletID)
+}
+
+// BedLightingOutletStatusContext is like BedLightingOutletStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedLightingOutletStatusContext(ctx context.Context, bedID string, outletID int) (UnderbedLightOutletStatus, error) {
 	var response UnderbedLightOutletStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/outlet?outletId={{outletId}}&_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/outlet?outletId={{outletId}}&_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 	url = strings.Replace(url, "{{outletId}}", strconv.Itoa(outletID), -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed lighting outlet status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed lighting outlet status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed lighting outlet status - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read bed lighting outlet status - %w", err)
 	}
 
 	return response, nil
@@ -678,35 +822,92 @@ type UnderbedLightSystemStatus struct {
 }
 
 // BedLightingSystemStatus retrieves the status of the underbed lighting system
-func (s SleepIQ) BedLightingSystemStatus(bedID string) (UnderbedLightSystemStatus, error) {
+func (s *SleepIQ) BedLightingSystemStatus(bedID string) (UnderbedLightSystemStatus, error) {
+	return s.BedLightingSystemStatusContext(context.Background(), bedID)
+}
+
+// BedLightingSystemStatusContext is like BedLightingSystemStatus but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) BedLightingSystemStatusContext(ctx context.Context, bedID string) (UnderbedLightSystemStatus, error) {
 	var response UnderbedLightSystemStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/system?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/system?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve bed lighting system status - %s", err)
+		return response, fmt.Errorf("unable to retrieve bed lighting system status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed lighting system status - %s", err)
+		return response, fmt.Errorf("could not read bed lighting system status - %w", err)
+	}
+
+	return response, nil
+}
+
+// ============================================================================
+// OUTLETS
+// ============================================================================
+
+// Outlet describes an accessory outlet of the bed and its current state
+type Outlet struct {
+	OutletID int    `json:"outletId"`
+	Name     string `json:"name"`
+	On       bool   `json:"on"`
+	Timer    int    `json:"timer"` // Minutes left before the outlet turns off or zero when no timer is running
+}
+
+// Outlets lists the accessory outlets of the bed with their names and
+// current state. Outlets without a name, such as the underbed light outlets,
+// are listed with an empty name.
+func (s *SleepIQ) Outlets(bedID string) ([]Outlet, error) {
+	return s.OutletsContext(context.Background(), bedID)
+}
+
+// OutletsContext is like Outlets but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) OutletsContext(ctx context.Context, bedID string) ([]Outlet, error) {
+	// The state comes from the foundation, the names from the smart outlets
+	details, err := s.BedDetailedStatusContext(ctx, bedID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve outlets - %w", err)
 	}
 
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+	names := make(map[int]string, len(details.Smartoutlets))
+	for _, smartOutlet := range details.Smartoutlets {
+		names[smartOutlet.OutletID] = smartOutlet.Name
 	}
 
-	return response, nil
+	outlets := make([]Outlet, 0, len(details.Foundation.Outlets))
+	for _, foundationOutlet := range details.Foundation.Outlets {
+		outlet := Outlet{
+			OutletID: foundationOutlet.OutletID,
+			Name:     names[foundationOutlet.OutletID],
+			On:       outletOn(foundationOutlet.Setting),
+		}
+
+		// Only the outlet status has the time left on an outlet that is on
+		if outlet.On {
+			status, err := s.BedLightingOutletStatusContext(ctx, bedID, outlet.OutletID)
+			if err != nil {
+				return nil, fmt.Errorf("unable to retrieve outlet %d - %w", outlet.OutletID, err)
+			}
+			outlet.Timer = outletTimer(status.Timer)
+		}
+
+		outlets = append(outlets, outlet)
+	}
+
+	return outlets, nil
 }
 
 // ============================================================================
@@ -722,3 +923,59 @@ func getHeaders() map[string]string {
 
 	return headers
 }
+
+// positionTimer decodes the position timer of the foundation, which is
+// reported in seconds as two hex encoded bytes
+func positionTimer(msb string, lsb string) (time.Duration, error) {
+	high, err := parseHexByte(msb)
+	if err != nil {
+		return 0, fmt.Errorf("could not read position timer - %w", err)
+	}
+
+	low, err := parseHexByte(lsb)
+	if err != nil {
+		return 0, fmt.Errorf("could not read position timer - %w", err)
+	}
+
+	return time.Duration(high<<8|low) * time.Second, nil
+}
+
+// parseHexByte parses a hex encoded byte such as "0x1e" as reported by the
+// foundation. An empty value is read as zero.
+func parseHexByte(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(value), "0x"), 16, 8)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(parsed), nil
+}
+
+// outletOn reads the setting of an outlet in the foundation status, which
+// is reported as a number or as a string
+func outletOn(setting interface{}) bool {
+	switch value := setting.(type) {
+	case float64:
+		return value != 0
+	case string:
+		return value != "" && value != "0"
+	}
+	return false
+}
+
+// outletTimer reads the timer of an outlet, which the service reports as a
+// number of minutes or null when no timer is running
+func outletTimer(timer interface{}) int {
+	switch value := timer.(type) {
+	case float64:
+		return int(value)
+	case string:
+		minutes, _ := strconv.Atoi(value)
+		return minutes
+	}
+	return 0
+}
@@ -2,24 +2,24 @@ package sleepiq
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
 )
 
 func TestBedSuccess(t *testing.T) {
-	siq := New()
+	server, siq := newTestServer()
+	defer server.Close()
 
 	// Test Beds() - Not Logged In
 	_, err := siq.Beds()
-	if err != nil {
-		if strings.Contains(err.Error(), "user is not logged-in") {
-			return
-		}
+	if err == nil || !strings.Contains(err.Error(), "user is not logged-in") {
+		t.Error("user shouldn't have been logged in")
 	}
-	t.Error("user shouldn't have been logged in")
 
-	response, err := siq.Login(os.Getenv("sleepiq_username"), os.Getenv("sleepiq_password"))
+	response, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
 	if err != nil {
 		t.Error("login failed - expected success", err)
 		return
@@ -166,3 +166,71 @@ func TestBedSuccess(t *testing.T) {
 		t.Errorf("bed light outlet system status is invalid")
 	}
 }
+
+func TestFoundationPositionTimer(t *testing.T) {
+	status := BedFoundationStatus{
+		LeftPositionTimerMSB:  "0x07",
+		LeftPositionTimerLSB:  "0x08",
+		RightPositionTimerMSB: "0x00",
+		RightPositionTimerLSB: "0x00",
+	}
+
+	timer, err := status.LeftPositionTimer()
+	if err != nil {
+		t.Fatalf("could not decode the left position timer - %s", err)
+	}
+
+	if timer != 30*time.Minute {
+		t.Errorf("failed to decode the left position timer. Expected=%s, Actual=%s", 30*time.Minute, timer)
+	}
+
+	timer, err = status.RightPositionTimer()
+	if err != nil || timer != 0 {
+		t.Errorf("failed to decode the right position timer. Expected=%s, Actual=%s (%v)", time.Duration(0), timer, err)
+	}
+
+	status.LeftPositionTimerLSB = "0xzz"
+	_, err = status.LeftPositionTimer()
+	if err == nil {
+		t.Error("decoding an invalid position timer succeeded - expected failure")
+	}
+}
+
+func TestOutlets(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.Outlets[0] = sleepiqtest.Outlet{Name: "Lamp", On: true, Timer: 30}
+		bed.Outlets[3] = sleepiqtest.Outlet{On: true, Timer: 15}
+	})
+
+	outlets, err := siq.Outlets(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not get outlets - %s", err)
+	}
+
+	if len(outlets) != 4 {
+		t.Fatalf("unexpected number of outlets. Expected=%d, Actual=%d", 4, len(outlets))
+	}
+
+	expected := Outlet{OutletID: OutletRightPlug, Name: "Lamp", On: true, Timer: 30}
+	if outlets[0] != expected {
+		t.Errorf("unexpected right outlet. Expected=%+v, Actual=%+v", expected, outlets[0])
+	}
+
+	if outlets[1].OutletID != OutletLeftPlug || outlets[1].On || outlets[1].Timer != 0 {
+		t.Errorf("unexpected left outlet. Actual=%+v", outlets[1])
+	}
+
+	// The underbed light outlets have no smart outlet entry and so no name
+	expected = Outlet{OutletID: OutletLeftUnderbedLight, On: true, Timer: 15}
+	if outlets[3] != expected {
+		t.Errorf("unexpected unnamed outlet. Expected=%+v, Actual=%+v", expected, outlets[3])
+	}
+}
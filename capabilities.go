@@ -0,0 +1,184 @@
+package sleepiq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// CAPABILITIES
+// ============================================================================
+
+// Feature is an optional feature of a bed. Features are bit flags and can
+// be combined.
+type Feature int
+
+// Bed features
+const (
+	FeatureAdjustableBase Feature = 1 << iota // FlexFit or other adjustable base
+	FeatureSplitBase                          // Each side of the base moves on its own
+	FeatureFootControl                        // Foot actuators can be moved
+	FeatureFootWarming
+	FeatureUnderbedLight
+	FeatureMassage
+	FeatureResponsiveAir
+)
+
+// featureNames holds the names of the features by bit
+var featureNames = []string{
+	"AdjustableBase",
+	"SplitBase",
+	"FootControl",
+	"FootWarming",
+	"UnderbedLight",
+	"Massage",
+	"ResponsiveAir",
+}
+
+// String lists the names of the features that are set
+func (f Feature) String() string {
+	var names []string
+	for bit, name := range featureNames {
+		if f&(1<<bit) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, "|")
+}
+
+// Bits of the fsBoardFeatures value reported by the foundation
+const (
+	boardFeatureSingle          = 0x01
+	boardFeatureMassageAndLight = 0x02
+	boardFeatureFootControl     = 0x04
+	boardFeatureFootWarming     = 0x08
+	boardFeatureUnderbedLight   = 0x10
+)
+
+// Capabilities describes the model of a bed and the features it has
+type Capabilities struct {
+	BedID      string
+	Model      string
+	Generation string
+	Base       string
+	DualSleep  bool
+	Features   Feature
+}
+
+// Has reports whether the bed has all of the given features
+func (c Capabilities) Has(features Feature) bool {
+	return c.Features&features == features
+}
+
+// Capabilities determines the features of the bed from its model, its base
+// and the features reported by the foundation board. The result is cached
+// for the lifetime of the SleepIQ instance.
+func (s *SleepIQ) Capabilities(bedID string) (Capabilities, error) {
+	return s.CapabilitiesContext(context.Background(), bedID)
+}
+
+// CapabilitiesContext is like Capabilities but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) CapabilitiesContext(ctx context.Context, bedID string) (Capabilities, error) {
+	s.capabilitiesMu.Lock()
+	capabilities, ok := s.capabilities[bedID]
+	s.capabilitiesMu.Unlock()
+	if ok {
+		return capabilities, nil
+	}
+
+	bed, err := s.findBed(ctx, bedID)
+	if err != nil {
+		return capabilities, fmt.Errorf("unable to retrieve bed capabilities - %w", err)
+	}
+
+	capabilities = Capabilities{
+		BedID:      bed.BedID,
+		Model:      bed.Model,
+		Generation: bed.Generation,
+		Base:       baseName(bed.Base),
+		DualSleep:  bed.DualSleep,
+	}
+
+	// Responsive air is only available on 360 smart beds
+	if capabilities.Generation == "360" {
+		capabilities.Features |= FeatureResponsiveAir
+	}
+
+	// Beds without a base have no foundation board to ask
+	if capabilities.Base != "" {
+		system, err := s.BedSystemStatusContext(ctx, bedID)
+		if err != nil {
+			return capabilities, fmt.Errorf("unable to retrieve bed capabilities - %w", err)
+		}
+		capabilities.Features |= boardFeatures(system.BoardFeatures)
+	}
+
+	s.capabilitiesMu.Lock()
+	if s.capabilities == nil {
+		s.capabilities = make(map[string]Capabilities)
+	}
+	s.capabilities[bedID] = capabilities
+	s.capabilitiesMu.Unlock()
+
+	return capabilities, nil
+}
+
+// requireFeatures returns ErrFeatureNotSupported if the bed does not have
+// all of the given features
+func (s *SleepIQ) requireFeatures(ctx context.Context, bedID string, features Feature) error {
+	capabilities, err := s.CapabilitiesContext(ctx, bedID)
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.Has(features) {
+		return fmt.Errorf("bed %s does not support %s - %w", bedID, features&^capabilities.Features, ErrFeatureNotSupported)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// SUPPORTING FUNCTIONS
+// ============================================================================
+
+// boardFeatures converts the features reported by the foundation board
+func boardFeatures(bits int) Feature {
+	features := FeatureAdjustableBase
+
+	if bits&boardFeatureSingle == 0 {
+		features |= FeatureSplitBase
+	}
+	if bits&boardFeatureMassageAndLight != 0 {
+		features |= FeatureMassage | FeatureUnderbedLight
+	}
+	if bits&boardFeatureFootControl != 0 {
+		features |= FeatureFootControl
+	}
+	if bits&boardFeatureFootWarming != 0 {
+		features |= FeatureFootWarming
+	}
+	if bits&boardFeatureUnderbedLight != 0 {
+		features |= FeatureUnderbedLight
+	}
+
+	return features
+}
+
+// baseName returns the name of the base of a bed, which the service reports
+// as a string or null when the bed has no adjustable base
+func baseName(base interface{}) string {
+	switch value := base.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	}
+	return fmt.Sprint(base)
+}
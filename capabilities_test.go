@@ -0,0 +1,134 @@
+package sleepiq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
+)
+
+func TestCapabilities(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	capabilities, err := siq.Capabilities(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not get bed capabilities - %s", err)
+	}
+
+	expected := FeatureAdjustableBase | FeatureSplitBase | FeatureFootControl | FeatureFootWarming | FeatureUnderbedLight | FeatureMassage | FeatureResponsiveAir
+	if capabilities.Features != expected {
+		t.Errorf("unexpected features. Expected=%s, Actual=%s", expected, capabilities.Features)
+	}
+
+	if capabilities.Model != "i8" || capabilities.Generation != "360" || capabilities.Base != "FlexFit 3" {
+		t.Errorf("unexpected bed model. Actual=%s/%s/%s", capabilities.Model, capabilities.Generation, capabilities.Base)
+	}
+
+	// The capabilities are cached
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.BoardFeatures = 0
+	})
+
+	capabilities, err = siq.Capabilities(sleepiqtest.DefaultBedID)
+	if err != nil || capabilities.Features != expected {
+		t.Errorf("capabilities were not cached. Expected=%s, Actual=%s (%v)", expected, capabilities.Features, err)
+	}
+
+	_, err = siq.Capabilities("unknown")
+	if err == nil {
+		t.Error("getting the capabilities of an unknown bed succeeded - expected failure")
+	}
+}
+
+func TestCapabilitiesWithoutBase(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.Generation = "c2"
+		bed.Base = ""
+	})
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	capabilities, err := siq.Capabilities(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not get bed capabilities - %s", err)
+	}
+
+	if capabilities.Features != 0 {
+		t.Errorf("unexpected features. Expected=%s, Actual=%s", Feature(0), capabilities.Features)
+	}
+
+	_, err = siq.ControlBedPosition(sleepiqtest.DefaultBedID, Left, PositionZeroG)
+	if !errors.Is(err, ErrFeatureNotSupported) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrFeatureNotSupported, err)
+	}
+
+	_, err = siq.ControlResponsiveAir(sleepiqtest.DefaultBedID, ResponsiveAirUpdate{InBedTimeout: new(int)})
+	if !errors.Is(err, ErrFeatureNotSupported) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrFeatureNotSupported, err)
+	}
+
+	// The sleep number can always be set
+	err = siq.ControlSleepNumber(sleepiqtest.DefaultBedID, Left, 40)
+	if err != nil {
+		t.Errorf("could not set sleep number - %s", err)
+	}
+}
+
+func TestControlFeatureNotSupported(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	// A single base without foot control or foot warming
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.BoardFeatures = 0x01 | 0x10
+	})
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	_, err = siq.ControlFootWarmer(sleepiqtest.DefaultBedID, Left, TempHigh, 30)
+	if !errors.Is(err, ErrFeatureNotSupported) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrFeatureNotSupported, err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.FootWarmingTemp != 0 {
+		t.Error("foot warmer should not have been changed")
+	}
+
+	err = siq.ControlActuatorPosition(sleepiqtest.DefaultBedID, Left, ActuatorFoot, 20)
+	if !errors.Is(err, ErrFeatureNotSupported) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrFeatureNotSupported, err)
+	}
+
+	err = siq.ControlActuatorPosition(sleepiqtest.DefaultBedID, Left, ActuatorHead, 20)
+	if err != nil {
+		t.Errorf("could not move head actuator - %s", err)
+	}
+
+	err = siq.ControlUnderbedLightSide(sleepiqtest.DefaultBedID, Left, 20, 0)
+	if err != nil {
+		t.Errorf("could not set underbed light - %s", err)
+	}
+}
+
+func TestFeatureString(t *testing.T) {
+	features := FeatureFootWarming | FeatureResponsiveAir
+	if features.String() != "FootWarming|ResponsiveAir" || Feature(0).String() != "None" {
+		t.Errorf("unexpected feature names. Actual=%s/%s", features, Feature(0))
+	}
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sleepiq "github.com/danpenn/SleepIQ"
+)
+
+// commands maps the command names to their implementation
+var commands = map[string]func(c *cli, args []string) error{
+	"beds":       (*cli).beds,
+	"status":     (*cli).status,
+	"position":   (*cli).position,
+	"number":     (*cli).number,
+	"footwarmer": (*cli).footWarmer,
+	"light":      (*cli).light,
+	"sleep":      (*cli).sleep,
+}
+
+// Names accepted for the bed positions, foot warmer temperatures and light
+// levels
+var (
+	positions = map[string]int{
+		"favorite": sleepiq.PositionFavorite,
+		"read":     sleepiq.PositionRead,
+		"watchtv":  sleepiq.PositionWatchTV,
+		"flat":     sleepiq.PositionFlat,
+		"zerog":    sleepiq.PositionZeroG,
+		"snore":    sleepiq.PositionSnore,
+	}
+
+	temperatures = map[string]sleepiq.FootWarmerLevel{
+		"off":    sleepiq.TempOff,
+		"low":    sleepiq.TempLow,
+		"medium": sleepiq.TempMedium,
+		"high":   sleepiq.TempHigh,
+	}
+
+	lightLevels = map[string]int{
+		"low":    sleepiq.LightLevelLow,
+		"medium": sleepiq.LightLevelMedium,
+		"high":   sleepiq.LightLevelHigh,
+	}
+)
+
+// ============================================================================
+// BED INFORMATION
+// ============================================================================
+
+// beds lists the beds of the account
+func (c *cli) beds(args []string) error {
+	if len(args) != 0 {
+		return c.usageError("beds takes no arguments")
+	}
+
+	beds, err := c.siq.BedsContext(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.print(beds, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "BED ID\tNAME\tMODEL\tSIZE")
+		for _, bed := range beds.Beds {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", bed.BedID, bed.Name, bed.Model, bed.Size)
+		}
+		tw.Flush()
+	})
+}
+
+// status shows the occupancy and sleep numbers of the beds
+func (c *cli) status(args []string) error {
+	if len(args) != 0 {
+		return c.usageError("status takes no arguments")
+	}
+
+	status, err := c.siq.BedFamilyStatusContext(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.print(status, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "BED ID\tSIDE\tIN BED\tSLEEP NUMBER\tPRESSURE")
+		for _, bed := range status.Beds {
+			for _, side := range sleepiq.Sides {
+				status := bed.Side(side)
+				fmt.Fprintf(tw, "%s\t%s\t%t\t%d\t%d\n", bed.BedID, side, status.IsInBed, status.SleepNumber, status.Pressure)
+			}
+		}
+		tw.Flush()
+	})
+}
+
+// sleep shows the sleep activity of the sleepers for a date, today by default
+func (c *cli) sleep(args []string) error {
+	if len(args) > 2 {
+		return c.usageError("usage: sleep [date] [interval]")
+	}
+
+	date := time.Now()
+	interval := ""
+
+	if len(args) > 0 {
+		var err error
+		date, err = time.ParseInLocation("2006-01-02", args[0], time.Local)
+		if err != nil {
+			return c.usageError("date must use the format 2006-01-02")
+		}
+	}
+	if len(args) > 1 {
+		interval = args[1]
+	}
+
+	activity, err := c.siq.SleepActivityContext(c.ctx, date, interval)
+	if err != nil {
+		return err
+	}
+
+	return c.print(activity, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SLEEPER ID\tSLEEPIQ\tIN BED\tRESTFUL\tRESTLESS\tHEART RATE\tBREATH RATE")
+		for _, sleeper := range activity.Sleepers {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%d\t%d\n",
+				sleeper.SleeperID,
+				sleeper.AvgSleepIQ,
+				time.Duration(sleeper.InBed)*time.Second,
+				time.Duration(sleeper.Restful)*time.Second,
+				time.Duration(sleeper.Restless)*time.Second,
+				sleeper.AvgHeartRate,
+				sleeper.AvgRespirationRate)
+		}
+		tw.Flush()
+	})
+}
+
+// ============================================================================
+// BED CONTROL
+// ============================================================================
+
+// position moves a side of the bed to a preset position
+func (c *cli) position(args []string) error {
+	flags, bedID := c.bedFlags("position set")
+	if len(args) == 0 || args[0] != "set" {
+		return c.usageError("usage: position set [-bed id] <side> <preset>")
+	}
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 2 {
+		return c.usageError("usage: position set [-bed id] <side> <preset>")
+	}
+
+	side, err := c.side(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	preset, ok := positions[strings.ToLower(flags.Arg(1))]
+	if !ok {
+		return c.usageError("preset must be favorite, read, watchtv, flat, zerog or snore")
+	}
+
+	id, err := c.bedID(*bedID)
+	if err != nil {
+		return err
+	}
+
+	status, err := c.siq.ControlBedPositionContext(c.ctx, id, side, preset)
+	if err != nil {
+		return err
+	}
+
+	return c.print(status, func(w io.Writer) {
+		fmt.Fprintf(w, "left: %s\nright: %s\n", status.CurrentPositionPresetLeft, status.CurrentPositionPresetRight)
+	})
+}
+
+// number sets the sleep number of a side of the bed, optionally waiting for
+// the pump to finish
+func (c *cli) number(args []string) error {
+	flags, bedID := c.bedFlags("number set")
+	wait := flags.Duration("wait", 0, "time to wait for the pump to finish (default no wait)")
+	if len(args) == 0 || args[0] != "set" {
+		return c.usageError("usage: number set [-bed id] [-wait timeout] <side> <number>")
+	}
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 2 {
+		return c.usageError("usage: number set [-bed id] [-wait timeout] <side> <number>")
+	}
+
+	side, err := c.side(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	sleepNumber, err := strconv.Atoi(flags.Arg(1))
+	if err != nil {
+		return c.usageError("sleep number must be a number between 1 and 100")
+	}
+
+	id, err := c.bedID(*bedID)
+	if err != nil {
+		return err
+	}
+
+	if *wait <= 0 {
+		err = c.siq.ControlSleepNumberContext(c.ctx, id, side, sleepNumber)
+		if err != nil {
+			return err
+		}
+
+		return c.done()
+	}
+
+	final, err := c.siq.ControlSleepNumberAndWaitContext(c.ctx, id, side, sleepNumber, *wait, func(progress sleepiq.PumpProgress) {
+		fmt.Fprintf(c.errOut, "%s: sleep number %d of %d\n", progress.Elapsed.Round(time.Second), progress.SleepNumber, progress.Target)
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.print(map[string]int{"sleepNumber": final}, func(w io.Writer) {
+		fmt.Fprintf(w, "%s: %d\n", side, final)
+	})
+}
+
+// footWarmer sets the temperature and duration of the foot warmer of a side
+func (c *cli) footWarmer(args []string) error {
+	flags, bedID := c.bedFlags("footwarmer")
+	if err := flags.Parse(args); err != nil || flags.NArg() < 2 || flags.NArg() > 3 {
+		return c.usageError("usage: footwarmer [-bed id] <side> <temp> [minutes]")
+	}
+
+	side, err := c.side(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	temperature, ok := temperatures[strings.ToLower(flags.Arg(1))]
+	if !ok {
+		return c.usageError("temperature must be off, low, medium or high")
+	}
+
+	duration, err := c.minutes(flags.Arg(2), 60)
+	if err != nil {
+		return err
+	}
+
+	id, err := c.bedID(*bedID)
+	if err != nil {
+		return err
+	}
+
+	status, err := c.siq.ControlFootWarmerContext(c.ctx, id, side, temperature, duration)
+	if err != nil {
+		return err
+	}
+
+	state := status.Decode()
+	return c.print(status, func(w io.Writer) {
+		fmt.Fprintf(w, "left: %s (%s)\nright: %s (%s)\n",
+			state.Left.Level, state.Left.Remaining, state.Right.Level, state.Right.Remaining)
+	})
+}
+
+// light turns the underbed light on at the given level or off, either for
+// both sides or for the side given with -side
+func (c *cli) light(args []string) error {
+	flags, bedID := c.bedFlags("light")
+	sideName := flags.String("side", "", "side of the bed (default both sides)")
+	if err := flags.Parse(args); err != nil || flags.NArg() < 1 || flags.NArg() > 2 {
+		return c.usageError("usage: light [-bed id] [-side side] <level> [minutes]")
+	}
+
+	// The level is a name or a brightness between 1 and 100
+	level := strings.ToLower(flags.Arg(0))
+	lightLevel, ok := lightLevels[level]
+	if !ok && level != "off" {
+		var err error
+		lightLevel, err = strconv.Atoi(level)
+		if err != nil {
+			return c.usageError("level must be off, low, medium, high or a brightness between 1 and 100")
+		}
+	}
+
+	duration, err := c.minutes(flags.Arg(1), 0)
+	if err != nil {
+		return err
+	}
+
+	var side sleepiq.Side
+	if *sideName != "" {
+		side, err = c.side(*sideName)
+		if err != nil {
+			return err
+		}
+	}
+
+	id, err := c.bedID(*bedID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case level == "off" && *sideName == "":
+		err = c.siq.ControlUnderbedLightOffContext(c.ctx, id)
+	case level == "off":
+		err = c.siq.ControlUnderbedLightSideOffContext(c.ctx, id, side)
+	case *sideName == "":
+		err = c.siq.ControlUnderbedLightContext(c.ctx, id, lightLevel, duration)
+	default:
+		err = c.siq.ControlUnderbedLightSideContext(c.ctx, id, side, lightLevel, duration)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.done()
+}
+
+// ============================================================================
+// SUPPORTING FUNCTIONS
+// ============================================================================
+
+// bedFlags returns the flags of a control command
+func (c *cli) bedFlags(name string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	bedID := flags.String("bed", "", "ID of the bed (default the first bed of the account)")
+	return flags, bedID
+}
+
+// bedID returns the given bed ID or the ID of the first bed of the account
+func (c *cli) bedID(bedID string) (string, error) {
+	if bedID != "" {
+		return bedID, nil
+	}
+
+	beds, err := c.siq.BedsContext(c.ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(beds.Beds) == 0 {
+		return "", fmt.Errorf("no beds were found in the account")
+	}
+
+	return beds.Beds[0].BedID, nil
+}
+
+// side parses a side argument
+func (c *cli) side(arg string) (sleepiq.Side, error) {
+	side, err := sleepiq.ParseSide(arg)
+	if err != nil {
+		return side, c.usageError("side must be left or right")
+	}
+
+	return side, nil
+}
+
+// minutes parses an optional duration argument
+func (c *cli) minutes(arg string, defaultMinutes int) (int, error) {
+	if arg == "" {
+		return defaultMinutes, nil
+	}
+
+	minutes, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, c.usageError("duration must be a number of minutes")
+	}
+
+	return minutes, nil
+}
+
+// done reports the success of a command that has no response
+func (c *cli) done() error {
+	return c.print(map[string]bool{"ok": true}, func(w io.Writer) {
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// usageError prints the message and returns errUsage
+func (c *cli) usageError(message string) error {
+	fmt.Fprintf(c.errOut, "sleepiq: %s\n", message)
+	return errUsage
+}
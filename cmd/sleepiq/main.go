@@ -0,0 +1,217 @@
+// Command sleepiq queries and controls SleepIQ beds from the command line.
+//
+// Usage:
+//
+//	sleepiq [-json] [-config file] <command> [arguments]
+//
+// The commands are:
+//
+//	beds                                       list the beds of the account
+//	status                                     show who is in bed and the sleep numbers
+//	position set [-bed id] <side> <preset>     move the bed to a preset position
+//	number set [-bed id] [-wait t] <side> <n>  set the sleep number of a side
+//	footwarmer [-bed id] <side> <temp> [min]   set the foot warmer of a side
+//	light [-bed id] [-side side] <level> [min] turn the underbed light on or off
+//	sleep [date] [interval]                    show the sleep activity of the sleepers
+//
+// The credentials are read from the sleepiq_username and sleepiq_password
+// environment variables or from a JSON config file, ~/.sleepiq.json by
+// default:
+//
+//	{"username": "sleeper@example.com", "password": "secret"}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	sleepiq "github.com/danpenn/SleepIQ"
+)
+
+// Environment variables holding the credentials and service URL. The names
+// match the ones used by the library tests.
+const (
+	envUsername = "sleepiq_username"
+	envPassword = "sleepiq_password"
+	envBaseURL  = "sleepiq_base_url"
+)
+
+// defaultConfigFile is the config file read when -config is not given
+const defaultConfigFile = ".sleepiq.json"
+
+// errUsage is returned when the command line is invalid. The usage has
+// already been printed when it is returned.
+var errUsage = errors.New("invalid usage")
+
+// config holds the settings read from the config file and the environment
+type config struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	BaseURL  string `json:"baseURL"`
+}
+
+const usage = `Usage: sleepiq [-json] [-config file] <command> [arguments]
+
+Commands:
+  beds                                       list the beds of the account
+  status                                     show who is in bed and the sleep numbers
+  position set [-bed id] <side> <preset>     move the bed to a preset position
+  number set [-bed id] [-wait t] <side> <n>  set the sleep number of a side
+  footwarmer [-bed id] <side> <temp> [min]   set the foot warmer of a side
+  light [-bed id] [-side side] <level> [min] turn the underbed light on or off
+  sleep [date] [interval]                    show the sleep activity of the sleepers
+
+Sides are left or right. Presets are favorite, read, watchtv, flat, zerog
+and snore. Foot warmer temperatures are off, low, medium and high. Light
+levels are off, low, medium, high or a brightness between 1 and 100. Dates
+use the format 2006-01-02 and intervals are d1, w1 or m1.
+
+Options:
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sleepiq: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command line and writes the result to stdout
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer, getenv func(string) string) error {
+	flags := flag.NewFlagSet("sleepiq", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	jsonOutput := flags.Bool("json", false, "print the responses of the service as JSON")
+	configFile := flags.String("config", "", "path of the config file (default ~/"+defaultConfigFile+")")
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	cfg, err := loadConfig(*configFile, getenv)
+	if err != nil {
+		return err
+	}
+
+	c := &cli{
+		ctx:    ctx,
+		out:    stdout,
+		errOut: stderr,
+		json:   *jsonOutput,
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "sleepiq: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return errUsage
+	}
+
+	if err := c.login(cfg); err != nil {
+		return err
+	}
+
+	return cmd(c, flags.Args()[1:])
+}
+
+// loadConfig reads the config file and applies the environment variables,
+// which take precedence over the file
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	var cfg config
+
+	// The default config file is optional but an explicit one must exist
+	explicit := path != ""
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			path = filepath.Join(home, defaultConfigFile)
+		}
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			err = json.Unmarshal(data, &cfg)
+			if err != nil {
+				return cfg, fmt.Errorf("could not read config file %s - %w", path, err)
+			}
+		case explicit || !errors.Is(err, os.ErrNotExist):
+			return cfg, fmt.Errorf("could not open config file - %w", err)
+		}
+	}
+
+	if value := getenv(envUsername); value != "" {
+		cfg.Username = value
+	}
+	if value := getenv(envPassword); value != "" {
+		cfg.Password = value
+	}
+	if value := getenv(envBaseURL); value != "" {
+		cfg.BaseURL = value
+	}
+
+	if cfg.Username == "" || cfg.Password == "" {
+		return cfg, fmt.Errorf("no credentials - set %s and %s or add them to the config file", envUsername, envPassword)
+	}
+
+	return cfg, nil
+}
+
+// cli holds the state shared by the commands
+type cli struct {
+	ctx    context.Context
+	siq    *sleepiq.SleepIQ
+	out    io.Writer
+	errOut io.Writer
+	json   bool
+}
+
+// login creates the client and logs in to the SleepIQ service
+func (c *cli) login(cfg config) error {
+	var options []sleepiq.Option
+	if cfg.BaseURL != "" {
+		options = append(options, sleepiq.WithBaseURL(cfg.BaseURL))
+	}
+
+	c.siq = sleepiq.New(options...)
+
+	_, err := c.siq.LoginContext(c.ctx, cfg.Username, cfg.Password)
+	return err
+}
+
+// print writes the response as JSON when -json is set and otherwise calls
+// text to write it in a human readable form
+func (c *cli) print(response interface{}, text func(w io.Writer)) error {
+	if !c.json {
+		text(c.out)
+		return nil
+	}
+
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(response)
+}
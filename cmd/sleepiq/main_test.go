@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sleepiq "github.com/danpenn/SleepIQ"
+	"github.com/danpenn/SleepIQ/sleepiqtest"
+)
+
+// runTest runs the command line against a fake SleepIQ service with the
+// credentials taken from the environment
+func runTest(t *testing.T, server *sleepiqtest.Server, args ...string) (string, error) {
+	env := map[string]string{
+		envUsername: sleepiqtest.DefaultUsername,
+		envPassword: sleepiqtest.DefaultPassword,
+		envBaseURL:  server.BaseURL(),
+	}
+
+	// Point the default config file to an empty directory
+	t.Setenv("HOME", t.TempDir())
+
+	var stdout bytes.Buffer
+	err := run(context.Background(), args, &stdout, io.Discard, func(key string) string { return env[key] })
+	return stdout.String(), err
+}
+
+func TestBeds(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+
+	output, err := runTest(t, server, "beds")
+	if err != nil {
+		t.Fatalf("beds failed - %s", err)
+	}
+
+	if !strings.Contains(output, sleepiqtest.DefaultBedID) {
+		t.Errorf("bed is missing from the output. Expected=%s, Actual=%s", sleepiqtest.DefaultBedID, output)
+	}
+}
+
+func TestStatusJSON(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+
+	output, err := runTest(t, server, "-json", "status")
+	if err != nil {
+		t.Fatalf("status failed - %s", err)
+	}
+
+	var status sleepiq.FamilyStatusDetails
+	err = json.Unmarshal([]byte(output), &status)
+	if err != nil {
+		t.Fatalf("could not read status output - %s", err)
+	}
+
+	if len(status.Beds) != 1 || status.Beds[0].BedID != sleepiqtest.DefaultBedID {
+		t.Errorf("unexpected status output: %s", output)
+	}
+}
+
+func TestNumberSet(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+
+	_, err := runTest(t, server, "number", "set", "left", "40")
+	if err != nil {
+		t.Fatalf("number set failed - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.SleepNumber != 40 {
+		t.Errorf("sleep number was not set. Expected=%d, Actual=%d", 40, bed.Left.SleepNumber)
+	}
+}
+
+func TestPositionSet(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+
+	output, err := runTest(t, server, "position", "set", "-bed", sleepiqtest.DefaultBedID, "right", "zerog")
+	if err != nil {
+		t.Fatalf("position set failed - %s", err)
+	}
+
+	if !strings.Contains(output, "right: Zero G") {
+		t.Errorf("position was not set. Output=%s", output)
+	}
+}
+
+func TestFootWarmer(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+
+	_, err := runTest(t, server, "footwarmer", "left", "high", "30")
+	if err != nil {
+		t.Fatalf("footwarmer failed - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.FootWarmingTemp != int(sleepiq.TempHigh) || bed.Left.FootWarmingTimer != 30 {
+		t.Errorf("foot warmer was not set. Expected=%d/%d, Actual=%d/%d", sleepiq.TempHigh, 30, bed.Left.FootWarmingTemp, bed.Left.FootWarmingTimer)
+	}
+}
+
+func TestInvalidUsage(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+
+	tests := [][]string{
+		{},
+		{"unknown"},
+		{"position", "left", "flat"},
+		{"position", "set", "left", "sideways"},
+		{"light", "bright"},
+	}
+
+	for _, args := range tests {
+		_, err := runTest(t, server, args...)
+		if !errors.Is(err, errUsage) {
+			t.Errorf("expected a usage error for %v. Actual=%v", args, err)
+		}
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"username":"user","password":"secret"}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path, func(key string) string {
+		if key == envPassword {
+			return "override"
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatalf("could not load config - %s", err)
+	}
+
+	if cfg.Username != "user" || cfg.Password != "override" {
+		t.Errorf("unexpected config. Expected=%s/%s, Actual=%s/%s", "user", "override", cfg.Username, cfg.Password)
+	}
+
+	_, err = loadConfig(filepath.Join(t.TempDir(), "missing.json"), func(string) string { return "" })
+	if err == nil {
+		t.Error("loading a missing config file succeeded - expected failure")
+	}
+}
+
+func TestLightSide(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+
+	_, err := runTest(t, server, "light", "-side", "right", "55")
+	if err != nil {
+		t.Fatalf("light failed - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Right.UnderbedLightPWM != 55 || !bed.Outlets[sleepiq.OutletRightUnderbedLight-1].On || bed.Outlets[sleepiq.OutletLeftUnderbedLight-1].On {
+		t.Errorf("only the right light should be on at 55. Actual=%d", bed.Right.UnderbedLightPWM)
+	}
+}
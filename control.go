@@ -2,11 +2,11 @@ package sleepiq
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
 )
 
 type controlResponse struct {
@@ -17,101 +17,187 @@ type controlResponse struct {
 // FOOT WARMER
 // ============================================================================
 
+// FootWarmerLevel is the temperature level of a foot warmer
+type FootWarmerLevel int
+
 // Footwarmer temperatures
 const (
-	TempOff    = 0
-	TempLow    = 31
-	TempMedium = 57
-	TempHigh   = 72
+	TempOff    FootWarmerLevel = 0
+	TempLow    FootWarmerLevel = 31
+	TempMedium FootWarmerLevel = 57
+	TempHigh   FootWarmerLevel = 72
 )
 
+// String returns the name of the foot warmer level
+func (l FootWarmerLevel) String() string {
+	switch l {
+	case TempOff:
+		return "Off"
+	case TempLow:
+		return "Low"
+	case TempMedium:
+		return "Medium"
+	case TempHigh:
+		return "High"
+	}
+	return fmt.Sprintf("FootWarmerLevel(%d)", int(l))
+}
+
+// valid reports whether the level is one of the Temp* constants
+func (l FootWarmerLevel) valid() bool {
+	return l == TempOff || l == TempLow || l == TempMedium || l == TempHigh
+}
+
+// FootWarmerSetting is the temperature and duration in minutes of the foot
+// warmer of one side of the bed. The duration may be zero when the level is
+// TempOff.
+type FootWarmerSetting struct {
+	Level    FootWarmerLevel
+	Duration int
+}
+
+// FootWarmerUpdate holds the foot warmer settings to change with
+// ControlFootWarmers. Sides that are nil are left unchanged.
+type FootWarmerUpdate struct {
+	Left  *FootWarmerSetting
+	Right *FootWarmerSetting
+}
+
+// footWarming describes the properties that are sent to control the foot
+// warmers. Settings that are nil are left unchanged.
+type footWarming struct {
+	TempLeft   *FootWarmerLevel `json:"footWarmingTempLeft,omitempty"`
+	TimerLeft  *int             `json:"footWarmingTimerLeft,omitempty"`
+	TempRight  *FootWarmerLevel `json:"footWarmingTempRight,omitempty"`
+	TimerRight *int             `json:"footWarmingTimerRight,omitempty"`
+}
+
 // ControlFootWarmer sets the foot warmer temperature and duration
-// for the given bed and side of bed
-func (s SleepIQ) ControlFootWarmer(bedID string, side string, temperature int, duration int) (FootWarmingStatus, error) {
-	var response FootWarmingStatus
+// for the given bed and side of bed. The duration may be zero when the foot
+// warmer is turned off.
+func (s *SleepIQ) ControlFootWarmer(bedID string, side Side, temperature FootWarmerLevel, duration int) (FootWarmingStatus, error) {
+	return s.ControlFootWarmerContext(context.Background(), bedID, side, temperature, duration)
+}
 
+// ControlFootWarmerContext is like ControlFootWarmer but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlFootWarmerContext(ctx context.Context, bedID string, side Side, temperature FootWarmerLevel, duration int) (FootWarmingStatus, error) {
 	// Validate parameters
-	if strings.ToLower(side) != "left" && strings.ToLower(side) != "right" {
-		return response, errors.New("parameter 'side' must be 'left' or 'right'")
+	if !side.valid() {
+		return FootWarmingStatus{}, ErrInvalidSide
 	}
 
-	if temperature != TempLow && temperature != TempMedium && temperature != TempHigh && temperature != TempOff {
-		return response, errors.New("parameter 'temperature' must be 'TempOff', 'TempLow', 'TempMedium' or 'TempHigh'")
+	// The setting is validated like a side of ControlFootWarmers
+	setting := &FootWarmerSetting{Level: temperature, Duration: duration}
+	if side == Left {
+		return s.ControlFootWarmersContext(ctx, bedID, FootWarmerUpdate{Left: setting})
 	}
+	return s.ControlFootWarmersContext(ctx, bedID, FootWarmerUpdate{Right: setting})
+}
 
-	if duration < 1 || duration > 360 {
-		return response, errors.New("parameter 'duration' must be between 0 and 360 inclusive")
+// ControlFootWarmers sets the foot warmers of both sides of the bed in a
+// single request and returns the updated foot warmer status
+func (s *SleepIQ) ControlFootWarmers(bedID string, update FootWarmerUpdate) (FootWarmingStatus, error) {
+	return s.ControlFootWarmersContext(context.Background(), bedID, update)
+}
+
+// ControlFootWarmersContext is like ControlFootWarmers but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlFootWarmersContext(ctx context.Context, bedID string, update FootWarmerUpdate) (FootWarmingStatus, error) {
+	// Validate parameters
+	if update.Left == nil && update.Right == nil {
+		return FootWarmingStatus{}, invalidParameter("parameter 'update' must change at least one side")
+	}
+
+	var payload footWarming
+	var err error
+	payload.TempLeft, payload.TimerLeft, err = footWarmingSide("Left", update.Left)
+	if err != nil {
+		return FootWarmingStatus{}, err
+	}
+	payload.TempRight, payload.TimerRight, err = footWarmingSide("Right", update.Right)
+	if err != nil {
+		return FootWarmingStatus{}, err
 	}
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return FootWarmingStatus{}, ErrNotLoggedIn
 	}
 
-	// Create JSON payload
-	payload := "{ \"footWarmingTemp{{side}}\": {{temp}}, \"footWarmingTimer{{side}}\": {{duration}} }"
-	payload = strings.Replace(payload, "{{side}}", strings.Title(side), -1)
-	payload = strings.Replace(payload, "{{temp}}", strconv.Itoa(temperature), -1)
-	payload = strings.Replace(payload, "{{duration}}", strconv.Itoa(duration), -1)
+	// Fail fast when the bed does not have the feature
+	err = s.requireFeatures(ctx, bedID, FeatureFootWarming)
+	if err != nil {
+		return FootWarmingStatus{}, err
+	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/footwarming?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/footwarming?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, _, err := httpPut(url, []byte(payload), s.cookies)
+	payloadBytes := new(bytes.Buffer)
+	json.NewEncoder(payloadBytes).Encode(payload)
+
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
 	if err != nil {
-		return response, fmt.Errorf("unable to set foot warmer - %s", err)
+		return FootWarmingStatus{}, fmt.Errorf("unable to set foot warmer - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	var footWarmingResponse controlResponse
 	err = json.Unmarshal(responseBytes, &footWarmingResponse)
 	if err != nil {
-		return response, fmt.Errorf("could not read foot warmer response - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if footWarmingResponse.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", footWarmingResponse.Error.Code, footWarmingResponse.Error.Message)
+		return FootWarmingStatus{}, fmt.Errorf("could not read foot warmer response - %w", err)
 	}
 
 	// Get the update foot warmer status
-	response, err = s.BedFootWarmerStatus(bedID)
+	response, err := s.BedFootWarmerStatusContext(ctx, bedID)
 	if err != nil {
-		return response, fmt.Errorf("could not get bed foot warmer status - %s", err)
+		return response, fmt.Errorf("could not get bed foot warmer status - %w", err)
 	}
 
-	// Marshal the response to a loginResponse object
-	err = json.Unmarshal(responseBytes, &response)
-	if err != nil {
-		return response, fmt.Errorf("could not read foot warmer response - %s", err)
-	}
+	return response, nil
+}
+
+// ControlFootWarmerOff turns the footwarmer off
+func (s *SleepIQ) ControlFootWarmerOff(bedID string) (FootWarmingStatus, error) {
+	return s.ControlFootWarmerOffContext(context.Background(), bedID)
+}
+
+// ControlFootWarmerOffContext is like ControlFootWarmerOff but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlFootWarmerOffContext(ctx context.Context, bedID string) (FootWarmingStatus, error) {
+	off := &FootWarmerSetting{Level: TempOff}
 
-	// Check for an error returned from the service
-	if footWarmingResponse.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", footWarmingResponse.Error.Code, footWarmingResponse.Error.Message)
+	response, err := s.ControlFootWarmersContext(ctx, bedID, FootWarmerUpdate{Left: off, Right: off})
+	if err != nil {
+		return response, fmt.Errorf("could not turn footwarmer off - %w", err)
 	}
 
 	return response, nil
 }
 
-// ControlFootWarmerOff turns the footwarmer off
-func (s SleepIQ) ControlFootWarmerOff(bedID string) (FootWarmingStatus, error) {
-	var response FootWarmingStatus
+// footWarmingSide validates the foot warmer setting of one side and returns
+// the values to send. The timer is left out when a side is turned off
+// without a duration.
+func footWarmingSide(side string, setting *FootWarmerSetting) (*FootWarmerLevel, *int, error) {
+	if setting == nil {
+		return nil, nil, nil
+	}
 
-	// Left Side
-	response, err := s.ControlFootWarmer(bedID, "Left", TempOff, 120)
-	if err != nil {
-		return response, fmt.Errorf("could not turn left footwarmer off - %s", err)
+	if !setting.Level.valid() {
+		return nil, nil, invalidParameter("parameter '" + side + ".Level' must be 'TempOff', 'TempLow', 'TempMedium' or 'TempHigh'")
 	}
 
-	// Right Side
-	response, err = s.ControlFootWarmer(bedID, "Right", TempOff, 120)
-	if err != nil {
-		return response, fmt.Errorf("could not turn right footwarmer off - %s", err)
+	if setting.Level == TempOff && setting.Duration == 0 {
+		return &setting.Level, nil, nil
 	}
 
-	return response, nil
+	if setting.Duration < 1 || setting.Duration > 360 {
+		return nil, nil, invalidParameter("parameter '" + side + ".Duration' must be between 1 and 360 inclusive")
+	}
+
+	return &setting.Level, &setting.Duration, nil
 }
 
 // ============================================================================
@@ -120,9 +206,10 @@ func (s SleepIQ) ControlFootWarmerOff(bedID string) (FootWarmingStatus, error) {
 
 // bedPosition describes the properties used to set the bed position
 type bedPosition struct {
-	Speed  int    `json:"speed"`
-	Side   string `json:"side"`
-	Preset int    `json:"preset"`
+	Speed  int      `json:"speed"`
+	Side   sideCode `json:"side"`
+	Preset int      `json:"preset"`
+	Timer  int      `json:"timer,omitempty"`
 }
 
 // Bed Preset Positions
@@ -137,70 +224,284 @@ const (
 
 // ControlBedPosition controls the position of the bed using preset
 // bed positions
-func (s SleepIQ) ControlBedPosition(bedID string, side string, position int) (BedFoundationStatus, error) {
+func (s *SleepIQ) ControlBedPosition(bedID string, side Side, position int) (BedFoundationStatus, error) {
+	return s.ControlBedPositionContext(context.Background(), bedID, side, position)
+}
+
+// ControlBedPositionContext is like ControlBedPosition but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlBedPositionContext(ctx context.Context, bedID string, side Side, position int) (BedFoundationStatus, error) {
+	return s.controlBedPosition(ctx, bedID, side, position, 0)
+}
+
+// ControlBedPositionTimer moves the bed to a preset position for the given
+// number of minutes, after which the bed returns to flat
+func (s *SleepIQ) ControlBedPositionTimer(bedID string, side Side, position int, duration int) (BedFoundationStatus, error) {
+	return s.ControlBedPositionTimerContext(context.Background(), bedID, side, position, duration)
+}
+
+// ControlBedPositionTimerContext is like ControlBedPositionTimer but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlBedPositionTimerContext(ctx context.Context, bedID string, side Side, position int, duration int) (BedFoundationStatus, error) {
+	if duration < 1 || duration > 180 {
+		return BedFoundationStatus{}, invalidParameter("parameter 'duration' must be between 1 and 180 minutes inclusive")
+	}
+
+	return s.controlBedPosition(ctx, bedID, side, position, duration)
+}
+
+// controlBedPosition moves the bed to a preset position. A duration of zero
+// keeps the bed in the position until it is changed.
+func (s *SleepIQ) controlBedPosition(ctx context.Context, bedID string, side Side, position int, duration int) (BedFoundationStatus, error) {
 	var response BedFoundationStatus
 
 	// Validate parameters
-	if strings.ToLower(side) != "left" && strings.ToLower(side) != "right" {
-		return response, errors.New("parameter 'side' must be 'left' or 'right'")
+	if !side.valid() {
+		return response, ErrInvalidSide
 	}
 
 	if position < 1 || position > 6 {
-		return response, errors.New("parameter 'position' must be between 1 and 6 inclusive")
+		return response, invalidParameter("parameter 'position' must be between 1 and 6 inclusive")
 	}
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureAdjustableBase)
+	if err != nil {
+		return response, err
 	}
 
 	// Create JSON payload
 	payload := bedPosition{
 		Preset: position,
-		Side:   strings.ToUpper(side[0:1]),
+		Side:   sideCode(side),
+		Timer:  duration,
 	}
 	payloadBytes := new(bytes.Buffer)
 	json.NewEncoder(payloadBytes).Encode(payload)
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/preset?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/preset?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, _, err := httpPut(url, payloadBytes.Bytes(), s.cookies)
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
 	if err != nil {
-		return response, fmt.Errorf("unable to set bed position - %s", err)
+		return response, fmt.Errorf("unable to set bed position - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	var controlResponse controlResponse
 	err = json.Unmarshal(responseBytes, &controlResponse)
 	if err != nil {
-		return response, fmt.Errorf("could not read control response - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if controlResponse.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", controlResponse.Error.Code, controlResponse.Error.Message)
+		return response, fmt.Errorf("could not read control response - %w", err)
 	}
 
 	// Get the bed foundation status
-	response, err = s.BedFoundationStatus(bedID)
+	response, err = s.BedFoundationStatusContext(ctx, bedID)
 	if err != nil {
-		return response, fmt.Errorf("could not get bed foundation status - %s", err)
+		return response, fmt.Errorf("could not get bed foundation status - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read bed foundation response - %s", err)
+		return response, fmt.Errorf("could not read bed foundation response - %w", err)
+	}
+
+	return response, nil
+}
+
+// ============================================================================
+// BED ARTICULATION
+// ============================================================================
+
+// actuatorPosition describes the properties used to move a single actuator
+// of the foundation to an absolute position
+type actuatorPosition struct {
+	Position int      `json:"position"`
+	Side     sideCode `json:"side"`
+	Actuator string   `json:"actuator"`
+	Speed    int      `json:"speed"`
+}
+
+// stopMotion describes the properties used to stop the motion of the foundation
+type stopMotion struct {
+	FootMotion    int      `json:"footMotion"`
+	HeadMotion    int      `json:"headMotion"`
+	MassageMotion int      `json:"massageMotion"`
+	Side          sideCode `json:"side"`
+}
+
+// Foundation actuators
+const (
+	ActuatorHead = "H"
+	ActuatorFoot = "F"
+)
+
+// foundationPollInterval is the time between status requests while waiting
+// for the foundation to stop moving
+var foundationPollInterval = time.Second
+
+// ControlActuatorPosition moves the head or foot actuator of the given side
+// of the bed to an absolute position between 0 (flat) and 100 (fully raised)
+func (s *SleepIQ) ControlActuatorPosition(bedID string, side Side, actuator string, position int) error {
+	return s.ControlActuatorPositionContext(context.Background(), bedID, side, actuator, position)
+}
+
+// ControlActuatorPositionContext is like ControlActuatorPosition but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlActuatorPositionContext(ctx context.Context, bedID string, side Side, actuator string, position int) error {
+	// Validate parameters
+	if !side.valid() {
+		return ErrInvalidSide
+	}
+
+	if actuator != ActuatorHead && actuator != ActuatorFoot {
+		return invalidParameter("parameter 'actuator' must be 'ActuatorHead' or 'ActuatorFoot'")
+	}
+
+	if position < 0 || position > 100 {
+		return invalidParameter("parameter 'position' must be between 0 and 100 inclusive")
+	}
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	features := FeatureAdjustableBase
+	if actuator == ActuatorFoot {
+		features |= FeatureFootControl
+	}
+
+	err := s.requireFeatures(ctx, bedID, features)
+	if err != nil {
+		return err
 	}
 
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+	// Create JSON payload
+	payload := actuatorPosition{
+		Position: position,
+		Side:     sideCode(side),
+		Actuator: actuator,
 	}
+	payloadBytes := new(bytes.Buffer)
+	json.NewEncoder(payloadBytes).Encode(payload)
 
-	return response, nil
+	// Make request
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/adjustment/micro?_k={{key}}", "{{key}}", s.auth.key(), -1)
+	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to set actuator position - %w", err)
+	}
+
+	// Marshal the response to a loginResponse object
+	var controlResponse controlResponse
+	err = json.Unmarshal(responseBytes, &controlResponse)
+	if err != nil {
+		return fmt.Errorf("could not read control response - %w", err)
+	}
+
+	return nil
+}
+
+// ControlStopMotion stops all motion of the given side of the bed, including
+// the massage
+func (s *SleepIQ) ControlStopMotion(bedID string, side Side) error {
+	return s.ControlStopMotionContext(context.Background(), bedID, side)
+}
+
+// ControlStopMotionContext is like ControlStopMotion but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlStopMotionContext(ctx context.Context, bedID string, side Side) error {
+	// Validate parameters
+	if !side.valid() {
+		return ErrInvalidSide
+	}
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureAdjustableBase)
+	if err != nil {
+		return err
+	}
+
+	// Create JSON payload
+	payload := stopMotion{
+		FootMotion:    1,
+		HeadMotion:    1,
+		MassageMotion: 1,
+		Side:          sideCode(side),
+	}
+	payloadBytes := new(bytes.Buffer)
+	json.NewEncoder(payloadBytes).Encode(payload)
+
+	// Make request
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/motion?_k={{key}}", "{{key}}", s.auth.key(), -1)
+	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to stop bed motion - %w", err)
+	}
+
+	// Marshal the response to a loginResponse object
+	var controlResponse controlResponse
+	err = json.Unmarshal(responseBytes, &controlResponse)
+	if err != nil {
+		return fmt.Errorf("could not read control response - %w", err)
+	}
+
+	return nil
+}
+
+// WaitForFoundation polls the foundation status until the bed has stopped
+// moving and returns the final status. An error is returned if the bed is
+// still moving after the timeout.
+func (s *SleepIQ) WaitForFoundation(bedID string, timeout time.Duration) (BedFoundationStatus, error) {
+	return s.WaitForFoundationContext(context.Background(), bedID, timeout)
+}
+
+// WaitForFoundationContext is like WaitForFoundation but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) WaitForFoundationContext(ctx context.Context, bedID string, timeout time.Duration) (BedFoundationStatus, error) {
+	// Validate Parameters
+	if timeout <= 0 {
+		return BedFoundationStatus{}, invalidParameter("parameter 'timeout' must be greater than zero")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		response, err := s.BedFoundationStatusContext(ctx, bedID)
+		if err != nil {
+			return response, fmt.Errorf("could not wait for the bed to stop moving - %w", err)
+		}
+
+		if !response.IsMoving {
+			return response, nil
+		}
+
+		timer := time.NewTimer(foundationPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, fmt.Errorf("bed is still moving - %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
 }
 
 // ============================================================================
@@ -216,103 +517,254 @@ type underbedLightOutlet struct {
 }
 
 // underbedLightSystem describes the properties that are sent to control
-// the underbed lighting system
+// the underbed lighting system. Sides that are nil are left unchanged.
 type underbedLightSystem struct {
-	RightUnderbedLightPWM int `json:"rightUnderbedLightPWM"`
-	LeftUnderbedLightPWM  int `json:"leftUnderbedLightPWM"`
+	RightUnderbedLightPWM *int `json:"rightUnderbedLightPWM,omitempty"`
+	LeftUnderbedLightPWM  *int `json:"leftUnderbedLightPWM,omitempty"`
 }
 
-// Underbed Lighting Levels
+// Underbed Lighting Levels. Any brightness between LightLevelLow and
+// LightLevelHigh can be used.
 const (
 	LightLevelLow    = 1
 	LightLevelMedium = 30
 	LightLevelHigh   = 100
 )
 
-// ControlUnderbedLight controls the underbed lighting system
-func (s SleepIQ) ControlUnderbedLight(bedID string, lightLevel int, duration int) error {
+// Underbed light outlets
+const (
+	OutletRightUnderbedLight = 3
+	OutletLeftUnderbedLight  = 4
+)
+
+// ControlUnderbedLight turns the underbed lights of both sides on at the
+// given brightness for the given number of minutes. A duration of zero
+// keeps the lights on until they are turned off.
+func (s *SleepIQ) ControlUnderbedLight(bedID string, lightLevel int, duration int) error {
+	return s.ControlUnderbedLightContext(context.Background(), bedID, lightLevel, duration)
+}
+
+// ControlUnderbedLightContext is like ControlUnderbedLight but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlUnderbedLightContext(ctx context.Context, bedID string, lightLevel int, duration int) error {
 	// Validate parameters
-	if lightLevel != LightLevelLow && lightLevel != LightLevelMedium && lightLevel != LightLevelHigh {
-		return errors.New("parameter 'lightLevel' must be 'LightLevelLow', 'LightLevelMedium' or 'LightLevelHigh'")
+	if lightLevel < LightLevelLow || lightLevel > LightLevelHigh {
+		return invalidParameter("parameter 'lightLevel' must be between 1 and 100 inclusive")
 	}
 
 	if duration < 0 || duration > 180 {
-		return errors.New("parameter 'duration' must be between 0 and 180 minutes inclusive")
+		return invalidParameter("parameter 'duration' must be between 0 and 180 minutes inclusive")
 	}
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
 	}
 
-	// Make request - First we need to set the system status
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/system?_k={{key}}", "{{key}}", s.loginKey, -1)
-	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureUnderbedLight)
+	if err != nil {
+		return err
+	}
 
-	// Create JSON payload
-	payload := underbedLightSystem{
-		RightUnderbedLightPWM: lightLevel,
-		LeftUnderbedLightPWM:  lightLevel,
+	// First we need to set the brightness, then turn the outlets on
+	err = s.setUnderbedLightLevel(ctx, bedID, underbedLightSystem{
+		RightUnderbedLightPWM: &lightLevel,
+		LeftUnderbedLightPWM:  &lightLevel,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, outletID := range []int{OutletRightUnderbedLight, OutletLeftUnderbedLight} {
+		err = s.setOutlet(ctx, bedID, outletID, true, duration)
+		if err != nil {
+			return fmt.Errorf("unable to set light outlet duration - %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ControlUnderbedLightOff turns the underbed lights of both sides off
+func (s *SleepIQ) ControlUnderbedLightOff(bedID string) error {
+	return s.ControlUnderbedLightOffContext(context.Background(), bedID)
+}
+
+// ControlUnderbedLightOffContext is like ControlUnderbedLightOff but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlUnderbedLightOffContext(ctx context.Context, bedID string) error {
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureUnderbedLight)
+	if err != nil {
+		return err
+	}
+
+	for _, outletID := range []int{OutletRightUnderbedLight, OutletLeftUnderbedLight} {
+		err := s.setOutlet(ctx, bedID, outletID, false, 0)
+		if err != nil {
+			return fmt.Errorf("unable to turn light off - %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ControlUnderbedLightSide turns the underbed light of one side of the bed
+// on at the given brightness for the given number of minutes. The light of
+// the other side is left unchanged.
+func (s *SleepIQ) ControlUnderbedLightSide(bedID string, side Side, lightLevel int, duration int) error {
+	return s.ControlUnderbedLightSideContext(context.Background(), bedID, side, lightLevel, duration)
+}
+
+// ControlUnderbedLightSideContext is like ControlUnderbedLightSide but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlUnderbedLightSideContext(ctx context.Context, bedID string, side Side, lightLevel int, duration int) error {
+	// Validate parameters
+	if !side.valid() {
+		return ErrInvalidSide
 	}
 
+	if lightLevel < LightLevelLow || lightLevel > LightLevelHigh {
+		return invalidParameter("parameter 'lightLevel' must be between 1 and 100 inclusive")
+	}
+
+	if duration < 0 || duration > 180 {
+		return invalidParameter("parameter 'duration' must be between 0 and 180 minutes inclusive")
+	}
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureUnderbedLight)
+	if err != nil {
+		return err
+	}
+
+	// First we need to set the brightness, then turn the outlet on
+	var system underbedLightSystem
+	outletID := OutletRightUnderbedLight
+	if side == Left {
+		system.LeftUnderbedLightPWM = &lightLevel
+		outletID = OutletLeftUnderbedLight
+	} else {
+		system.RightUnderbedLightPWM = &lightLevel
+	}
+
+	err = s.setUnderbedLightLevel(ctx, bedID, system)
+	if err != nil {
+		return err
+	}
+
+	err = s.setOutlet(ctx, bedID, outletID, true, duration)
+	if err != nil {
+		return fmt.Errorf("unable to set light outlet duration - %w", err)
+	}
+
+	return nil
+}
+
+// ControlUnderbedLightSideOff turns the underbed light of one side of the bed off
+func (s *SleepIQ) ControlUnderbedLightSideOff(bedID string, side Side) error {
+	return s.ControlUnderbedLightSideOffContext(context.Background(), bedID, side)
+}
+
+// ControlUnderbedLightSideOffContext is like ControlUnderbedLightSideOff but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlUnderbedLightSideOffContext(ctx context.Context, bedID string, side Side) error {
+	// Validate parameters
+	if !side.valid() {
+		return ErrInvalidSide
+	}
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureUnderbedLight)
+	if err != nil {
+		return err
+	}
+
+	outletID := OutletRightUnderbedLight
+	if side == Left {
+		outletID = OutletLeftUnderbedLight
+	}
+
+	err = s.setOutlet(ctx, bedID, outletID, false, 0)
+	if err != nil {
+		return fmt.Errorf("unable to turn light off - %w", err)
+	}
+
+	return nil
+}
+
+// setUnderbedLightLevel sets the brightness of the underbed lights
+func (s *SleepIQ) setUnderbedLightLevel(ctx context.Context, bedID string, system underbedLightSystem) error {
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/system?_k={{key}}", "{{key}}", s.auth.key(), -1)
+	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+
 	payloadBytes := new(bytes.Buffer)
-	json.NewEncoder(payloadBytes).Encode(payload)
+	json.NewEncoder(payloadBytes).Encode(system)
 
-	responseBytes, _, err := httpPut(url, payloadBytes.Bytes(), s.cookies)
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
 	if err != nil {
-		return fmt.Errorf("unable to set light system duration - %s", err)
+		return fmt.Errorf("unable to set light system level - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	var controlResponse controlResponse
 	err = json.Unmarshal(responseBytes, &controlResponse)
 	if err != nil {
-		return fmt.Errorf("could not read control response - %s", err)
+		return fmt.Errorf("could not read control response - %w", err)
 	}
 
-	// Check for an error returned from the service
-	if controlResponse.Error.Code > 0 {
-		return fmt.Errorf("error #%d: %s", controlResponse.Error.Code, controlResponse.Error.Message)
-	}
+	return nil
+}
 
-	// Make request - Last we need to set the outlet status
-	url = strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/outlet?_k={{key}}", "{{key}}", s.loginKey, -1)
+// setOutlet turns a foundation outlet on for the given number of minutes or
+// off. A duration of zero keeps the outlet on until it is turned off.
+func (s *SleepIQ) setOutlet(ctx context.Context, bedID string, outletID int, on bool, duration int) error {
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/outlet?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	// Create JSON payload
-	payloadOutlet := underbedLightOutlet{
-		OutletID: 3,
-		Setting:  "1",
+	payload := underbedLightOutlet{
+		OutletID: outletID,
+		Setting:  "0",
 		Timer:    duration,
 	}
+	if on {
+		payload.Setting = "1"
+	}
+
+	payloadBytes := new(bytes.Buffer)
+	json.NewEncoder(payloadBytes).Encode(payload)
 
-	payloadOutletBytes := new(bytes.Buffer)
-	json.NewEncoder(payloadOutletBytes).Encode(payloadOutlet)
-	fmt.Println(string(payloadOutletBytes.Bytes()))
-	responseBytes, _, err = httpPut(url, payloadOutletBytes.Bytes(), s.cookies)
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
 	if err != nil {
-		return fmt.Errorf("unable to set light outlet duration - %s", err)
+		return err
 	}
 
 	// Marshal the response to a loginResponse object
+	var controlResponse controlResponse
 	err = json.Unmarshal(responseBytes, &controlResponse)
 	if err != nil {
-		return fmt.Errorf("could not read control response - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if controlResponse.Error.Code > 0 {
-		return fmt.Errorf("error #%d: %s", controlResponse.Error.Code, controlResponse.Error.Message)
+		return fmt.Errorf("could not read control response - %w", err)
 	}
 
 	return nil
 }
 
-// ControlUnderbedLightOff turns the underbed light off
-func (s SleepIQ) ControlUnderbedLightOff(bedID string) error {
-	return s.ControlUnderbedLight(bedID, LightLevelHigh, 0)
-}
-
 // autoUnderbedLight describes the properties that are sent to control
 // the underbed lighting automatic mode when a person leaves the bed
 type autoUnderbedLight struct {
@@ -320,14 +772,26 @@ type autoUnderbedLight struct {
 }
 
 // ControlUnderbedLightAutoMode controls the auto mode of the underbed light
-func (s SleepIQ) ControlUnderbedLightAutoMode(bedID string, enabled bool) error {
+func (s *SleepIQ) ControlUnderbedLightAutoMode(bedID string, enabled bool) error {
+	return s.ControlUnderbedLightAutoModeContext(context.Background(), bedID, enabled)
+}
+
+// ControlUnderbedLightAutoModeContext is like ControlUnderbedLightAutoMode but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlUnderbedLightAutoModeContext(ctx context.Context, bedID string, enabled bool) error {
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureUnderbedLight)
+	if err != nil {
+		return err
 	}
 
 	// Make request - First we need to set the system status
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/foundation/underbedLight?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/foundation/underbedLight?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
 	// Create JSON payload
@@ -338,21 +802,64 @@ func (s SleepIQ) ControlUnderbedLightAutoMode(bedID string, enabled bool) error
 	payloadBytes := new(bytes.Buffer)
 	json.NewEncoder(payloadBytes).Encode(payload)
 
-	responseBytes, _, err := httpPut(url, payloadBytes.Bytes(), s.cookies)
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
 	if err != nil {
-		return fmt.Errorf("unable to set light auto mode - %s", err)
+		return fmt.Errorf("unable to set light auto mode - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	var controlResponse controlResponse
 	err = json.Unmarshal(responseBytes, &controlResponse)
 	if err != nil {
-		return fmt.Errorf("could not read control response - %s", err)
+		return fmt.Errorf("could not read control response - %w", err)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// OUTLETS
+// ============================================================================
+
+// Accessory outlets in the base of the bed
+const (
+	OutletRightPlug = 1
+	OutletLeftPlug  = 2
+)
+
+// ControlOutlet turns an accessory outlet of the bed on for the given number
+// of minutes or off. A timer of zero keeps the outlet on until it is turned
+// off.
+func (s *SleepIQ) ControlOutlet(bedID string, outletID int, on bool, timer int) error {
+	return s.ControlOutletContext(context.Background(), bedID, outletID, on, timer)
+}
+
+// ControlOutletContext is like ControlOutlet but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlOutletContext(ctx context.Context, bedID string, outletID int, on bool, timer int) error {
+	// Validate parameters
+	if outletID != OutletRightPlug && outletID != OutletLeftPlug {
+		return invalidParameter("parameter 'outletID' must be 'OutletRightPlug' or 'OutletLeftPlug'")
 	}
 
-	// Check for an error returned from the service
-	if controlResponse.Error.Code > 0 {
-		return fmt.Errorf("error #%d: %s", controlResponse.Error.Code, controlResponse.Error.Message)
+	if timer < 0 || timer > 180 {
+		return invalidParameter("parameter 'timer' must be between 0 and 180 minutes inclusive")
+	}
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureAdjustableBase)
+	if err != nil {
+		return err
+	}
+
+	err = s.setOutlet(ctx, bedID, outletID, on, timer)
+	if err != nil {
+		return fmt.Errorf("unable to set outlet - %w", err)
 	}
 
 	return nil
@@ -362,49 +869,102 @@ func (s SleepIQ) ControlUnderbedLightAutoMode(bedID string, enabled bool) error
 // RESPONSIVE AIR
 // ============================================================================
 
-// responsiveAirMode describes the properties that are sent to control
-// the enabledment of the responsive air system
-type responsiveAirMode struct {
-	RightSideEnabled bool `json:"rightSideEnabled"`
+// ResponsiveAirUpdate describes changes to the responsive air settings of a
+// bed. Settings that are nil are left unchanged.
+type ResponsiveAirUpdate struct {
+	LeftSideEnabled     *bool `json:"leftSideEnabled,omitempty"`
+	RightSideEnabled    *bool `json:"rightSideEnabled,omitempty"`
+	InBedTimeout        *int  `json:"inBedTimeout,omitempty"`
+	OutOfBedTimeout     *int  `json:"outOfBedTimeout,omitempty"`
+	AdjustmentThreshold *int  `json:"adjustmentThreshold,omitempty"`
+	PollFrequency       *int  `json:"pollFrequency,omitempty"`
 }
 
-// ControlResponsiveAirMode controls the enablement of the responsive air mode
-func (s SleepIQ) ControlResponsiveAirMode(bedID string, enabled bool) error {
-	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return errors.New("user is not logged-in. Please login and try again")
+// ControlResponsiveAir updates the responsive air settings of the bed and
+// returns the settings after the update
+func (s *SleepIQ) ControlResponsiveAir(bedID string, update ResponsiveAirUpdate) (ResponsiveAirSettings, error) {
+	return s.ControlResponsiveAirContext(context.Background(), bedID, update)
+}
+
+// ControlResponsiveAirContext is like ControlResponsiveAir but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlResponsiveAirContext(ctx context.Context, bedID string, update ResponsiveAirUpdate) (ResponsiveAirSettings, error) {
+	var response ResponsiveAirSettings
+
+	// Validate parameters
+	if update == (ResponsiveAirUpdate{}) {
+		return response, invalidParameter("parameter 'update' must change at least one setting")
 	}
 
-	// Make request - First we need to set the system status
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/responsiveAir?_k={{key}}", "{{key}}", s.loginKey, -1)
-	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+	settings := []struct {
+		name  string
+		value *int
+	}{
+		{"inBedTimeout", update.InBedTimeout},
+		{"outOfBedTimeout", update.OutOfBedTimeout},
+		{"adjustmentThreshold", update.AdjustmentThreshold},
+		{"pollFrequency", update.PollFrequency},
+	}
 
-	// Create JSON payload
-	payload := autoUnderbedLight{
-		EnableAuto: enabled,
+	for _, setting := range settings {
+		if setting.value != nil && *setting.value < 0 {
+			return response, invalidParameter("parameter '" + setting.name + "' must not be negative")
+		}
+	}
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
+	// Fail fast when the bed does not have the feature
+	err := s.requireFeatures(ctx, bedID, FeatureResponsiveAir)
+	if err != nil {
+		return response, err
+	}
+
+	// Make request
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/responsiveAir?_k={{key}}", "{{key}}", s.auth.key(), -1)
+	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+
 	payloadBytes := new(bytes.Buffer)
-	json.NewEncoder(payloadBytes).Encode(payload)
+	json.NewEncoder(payloadBytes).Encode(update)
 
-	responseBytes, _, err := httpPut(url, payloadBytes.Bytes(), s.cookies)
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
 	if err != nil {
-		return fmt.Errorf("unable to set responsive air mode - %s", err)
+		return response, fmt.Errorf("unable to set responsive air - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	var controlResponse controlResponse
 	err = json.Unmarshal(responseBytes, &controlResponse)
 	if err != nil {
-		return fmt.Errorf("could not read control response - %s", err)
+		return response, fmt.Errorf("could not read control response - %w", err)
 	}
 
-	// Check for an error returned from the service
-	if controlResponse.Error.Code > 0 {
-		return fmt.Errorf("error #%d: %s", controlResponse.Error.Code, controlResponse.Error.Message)
+	// Get the updated responsive air settings
+	response, err = s.BedResponsiveAirContext(ctx, bedID)
+	if err != nil {
+		return response, fmt.Errorf("could not get bed responsive air settings - %w", err)
 	}
 
-	return nil
+	return response, nil
+}
+
+// ControlResponsiveAirMode enables or disables responsive air on both sides
+// of the bed
+func (s *SleepIQ) ControlResponsiveAirMode(bedID string, enabled bool) error {
+	return s.ControlResponsiveAirModeContext(context.Background(), bedID, enabled)
+}
+
+// ControlResponsiveAirModeContext is like ControlResponsiveAirMode but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlResponsiveAirModeContext(ctx context.Context, bedID string, enabled bool) error {
+	_, err := s.ControlResponsiveAirContext(ctx, bedID, ResponsiveAirUpdate{
+		LeftSideEnabled:  &enabled,
+		RightSideEnabled: &enabled,
+	})
+	return err
 }
 
 // ============================================================================
@@ -414,96 +974,433 @@ func (s SleepIQ) ControlResponsiveAirMode(bedID string, enabled bool) error {
 //sleepNumberSettings describes the properties that are sent to control
 // the sleep number value
 type sleepNumberSettings struct {
-	Side        string `json:"side"`
-	SleepNumber int    `json:"sleepNumber"`
+	Side        sideCode `json:"side"`
+	SleepNumber int      `json:"sleepNumber"`
 }
 
 // ControlSleepNumber sets the sleep number for the bed
-func (s SleepIQ) ControlSleepNumber(bedID string, side string, sleepNumber int) error {
+func (s *SleepIQ) ControlSleepNumber(bedID string, side Side, sleepNumber int) error {
+	return s.ControlSleepNumberContext(context.Background(), bedID, side, sleepNumber)
+}
+
+// ControlSleepNumberContext is like ControlSleepNumber but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlSleepNumberContext(ctx context.Context, bedID string, side Side, sleepNumber int) error {
 	// Validate Parameters
-	if strings.ToLower(side) != "left" && strings.ToLower(side) != "right" {
-		return errors.New("parameter 'side' must be 'left' or 'right'")
+	if !side.valid() {
+		return ErrInvalidSide
 	}
 
 	if sleepNumber < 1 || sleepNumber > 100 {
-		return errors.New("parameter 'sleepNumber' must be between 1 and 100")
+		return invalidParameter("parameter 'sleepNumber' must be between 1 and 100")
 	}
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
 	}
 
 	// Make request - First we need to set the pump to idle
-	err := s.ControlPumpForceIdle(bedID)
+	err := s.ControlPumpForceIdleContext(ctx, bedID)
 	if err != nil {
-		return fmt.Errorf("unable to set pump to idle - %s", err)
+		return fmt.Errorf("unable to set pump to idle - %w", err)
 	}
 
 	// Make request - Last we need to set the sleep number
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/sleepNumber?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/sleepNumber?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
 	// Create JSON payload
 	payload := sleepNumberSettings{
-		Side:        side[0:1],
+		Side:        sideCode(side),
 		SleepNumber: sleepNumber,
 	}
 
 	payloadBytes := new(bytes.Buffer)
 	json.NewEncoder(payloadBytes).Encode(payload)
 
-	responseBytes, _, err := httpPut(url, payloadBytes.Bytes(), s.cookies)
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
 	if err != nil {
-		return fmt.Errorf("unable to set sleep number - %s", err)
+		return fmt.Errorf("unable to set sleep number - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	var controlResponse controlResponse
 	err = json.Unmarshal(responseBytes, &controlResponse)
 	if err != nil {
-		return fmt.Errorf("could not read control response - %s", err)
+		return fmt.Errorf("could not read control response - %w", err)
+	}
+
+	return nil
+}
+
+// sleepNumberRampStep is the largest change of the sleep number made at once
+// by ControlSleepNumberRamp
+const sleepNumberRampStep = 5
+
+// sleepNumberRampStepTimeout is the longest ControlSleepNumberRamp waits for
+// the pump to finish a step
+const sleepNumberRampStepTimeout = 2 * time.Minute
+
+// ControlSleepNumberRamp changes the sleep number of a side of the bed to the
+// given value in steps of at most 5 spread evenly over the period, so that
+// the sleeper does not notice a sudden change in firmness. Setting the sleep
+// number stops the pump, so every step waits for the pump to finish before the
+// pause to the next step begins. The ramp takes longer than the period when
+// the pump is slower than the pauses, and a period of zero makes the steps
+// back to back.
+func (s *SleepIQ) ControlSleepNumberRamp(bedID string, side Side, sleepNumber int, period time.Duration) error {
+	return s.ControlSleepNumberRampContext(context.Background(), bedID, side, sleepNumber, period)
+}
+
+// ControlSleepNumberRampContext is like ControlSleepNumberRamp but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlSleepNumberRampContext(ctx context.Context, bedID string, side Side, sleepNumber int, period time.Duration) error {
+	// Validate Parameters
+	if !side.valid() {
+		return ErrInvalidSide
+	}
+
+	if sleepNumber < 1 || sleepNumber > 100 {
+		return invalidParameter("parameter 'sleepNumber' must be between 1 and 100")
+	}
+
+	if period < 0 {
+		return invalidParameter("parameter 'period' must not be negative")
+	}
+
+	// Start from the current sleep number of the side
+	details, err := s.BedDetailedStatusContext(ctx, bedID)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve current sleep number - %w", err)
 	}
 
-	// Check for an error returned from the service
-	if controlResponse.Error.Code > 0 {
-		return fmt.Errorf("error #%d: %s", controlResponse.Error.Code, controlResponse.Error.Message)
+	current := details.Pump.RightSideSleepNumber
+	if side == Left {
+		current = details.Pump.LeftSideSleepNumber
+	}
+
+	steps := sleepNumberSteps(current, sleepNumber)
+	if len(steps) == 0 {
+		return nil
+	}
+	interval := period / time.Duration(len(steps))
+
+	for i, step := range steps {
+		if i > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("sleep number ramp stopped at %d - %w", steps[i-1], ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		err = s.ControlSleepNumberContext(ctx, bedID, side, step)
+		if err != nil {
+			return err
+		}
+
+		// The next step would stop the pump part way through this one
+		_, err = s.WaitForPumpContext(ctx, bedID, side, step, sleepNumberRampStepTimeout, nil)
+		if err != nil {
+			return fmt.Errorf("sleep number ramp stopped before reaching %d - %w", step, err)
+		}
+	}
+
+	return nil
+}
+
+// sleepNumberFavoriteSettings describes the properties that are sent to
+// control the favorite sleep number
+type sleepNumberFavoriteSettings struct {
+	BedID               string   `json:"bedId"`
+	SleepNumberFavorite int      `json:"sleepNumberFavorite"`
+	Side                sideCode `json:"side"`
+}
+
+// ControlSleepNumberFavorite sets the favorite sleep number of a side of the
+// bed. The current sleep number is not changed.
+func (s *SleepIQ) ControlSleepNumberFavorite(bedID string, side Side, sleepNumber int) error {
+	return s.ControlSleepNumberFavoriteContext(context.Background(), bedID, side, sleepNumber)
+}
+
+// ControlSleepNumberFavoriteContext is like ControlSleepNumberFavorite but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlSleepNumberFavoriteContext(ctx context.Context, bedID string, side Side, sleepNumber int) error {
+	// Validate Parameters
+	if !side.valid() {
+		return ErrInvalidSide
+	}
+
+	if sleepNumber < 1 || sleepNumber > 100 {
+		return invalidParameter("parameter 'sleepNumber' must be between 1 and 100")
+	}
+
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
+	}
+
+	// Make request
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/sleepNumberFavorite?_k={{key}}", "{{key}}", s.auth.key(), -1)
+	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+
+	// Create JSON payload
+	payload := sleepNumberFavoriteSettings{
+		BedID:               bedID,
+		SleepNumberFavorite: sleepNumber,
+		Side:                sideCode(side),
+	}
+
+	payloadBytes := new(bytes.Buffer)
+	json.NewEncoder(payloadBytes).Encode(payload)
+
+	responseBytes, _, err := s.httpPut(ctx, url, payloadBytes.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to set sleep number favorite - %w", err)
+	}
+
+	// Marshal the response to a loginResponse object
+	var controlResponse controlResponse
+	err = json.Unmarshal(responseBytes, &controlResponse)
+	if err != nil {
+		return fmt.Errorf("could not read control response - %w", err)
 	}
 
 	return nil
 }
 
+// ControlSleepNumberToFavorite sets the sleep number of a side of the bed to
+// its favorite sleep number
+func (s *SleepIQ) ControlSleepNumberToFavorite(bedID string, side Side) error {
+	return s.ControlSleepNumberToFavoriteContext(context.Background(), bedID, side)
+}
+
+// ControlSleepNumberToFavoriteContext is like ControlSleepNumberToFavorite but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlSleepNumberToFavoriteContext(ctx context.Context, bedID string, side Side) error {
+	// Validate Parameters
+	if !side.valid() {
+		return ErrInvalidSide
+	}
+
+	favorite, err := s.BedSleepNumberFavoriteContext(ctx, bedID)
+	if err != nil {
+		return err
+	}
+
+	sleepNumber := favorite.SleepNumberFavoriteRight
+	if side == Left {
+		sleepNumber = favorite.SleepNumberFavoriteLeft
+	}
+
+	return s.ControlSleepNumberContext(ctx, bedID, side, sleepNumber)
+}
+
+// sleepNumberSteps returns the sleep numbers to go through to get from one
+// sleep number to another, ending with the target
+func sleepNumberSteps(from int, to int) []int {
+	var steps []int
+	for from != to {
+		switch {
+		case to-from > sleepNumberRampStep:
+			from += sleepNumberRampStep
+		case from-to > sleepNumberRampStep:
+			from -= sleepNumberRampStep
+		default:
+			from = to
+		}
+		steps = append(steps, from)
+	}
+	return steps
+}
+
 // ============================================================================
 // PUMP
 // ============================================================================
 
+// pumpPollInterval is the time between status requests while waiting for
+// the pump to finish adjusting the sleep number
+var pumpPollInterval = time.Second
+
+// PumpProgress describes the state of the pump while waiting for it to
+// finish adjusting the sleep number
+type PumpProgress struct {
+	ActiveTask  int           // Task the pump is working on or zero when it is idle
+	SleepNumber int           // Sleep number of the side reported by the pump
+	Target      int           // Sleep number the side is being adjusted to
+	Elapsed     time.Duration // Time since the wait started
+}
+
+// ControlSleepNumberAndWait sets the sleep number for the bed and waits for
+// the side to settle at it, returning the final sleep number of the side.
+// See WaitForPump for the timeout and progress callback.
+func (s *SleepIQ) ControlSleepNumberAndWait(bedID string, side Side, sleepNumber int, timeout time.Duration, progress func(PumpProgress)) (int, error) {
+	return s.ControlSleepNumberAndWaitContext(context.Background(), bedID, side, sleepNumber, timeout, progress)
+}
+
+// ControlSleepNumberAndWaitContext is like ControlSleepNumberAndWait but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlSleepNumberAndWaitContext(ctx context.Context, bedID string, side Side, sleepNumber int, timeout time.Duration, progress func(PumpProgress)) (int, error) {
+	err := s.ControlSleepNumberContext(ctx, bedID, side, sleepNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.WaitForPumpContext(ctx, bedID, side, sleepNumber, timeout, progress)
+}
+
+// WaitForPump polls the pump until it has finished adjusting the side to the
+// given sleep number and returns the sleep number of the side from the family
+// status. The pump may not have started right after a change is sent, so the
+// wait only ends once the pump has been busy and is idle again, or is idle
+// with the side at the sleep number. The side may settle at another sleep
+// number, for example when it is changed from the remote. The progress
+// callback, which may be nil, is called after every poll. An error is returned
+// if the pump has not finished after the timeout.
+func (s *SleepIQ) WaitForPump(bedID string, side Side, sleepNumber int, timeout time.Duration, progress func(PumpProgress)) (int, error) {
+	return s.WaitForPumpContext(context.Background(), bedID, side, sleepNumber, timeout, progress)
+}
+
+// WaitForPumpContext is like WaitForPump but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) WaitForPumpContext(ctx context.Context, bedID string, side Side, sleepNumber int, timeout time.Duration, progress func(PumpProgress)) (int, error) {
+	// Validate Parameters
+	if !side.valid() {
+		return 0, ErrInvalidSide
+	}
+
+	if sleepNumber < 1 || sleepNumber > 100 {
+		return 0, invalidParameter("parameter 'sleepNumber' must be between 1 and 100")
+	}
+
+	if timeout <= 0 {
+		return 0, invalidParameter("parameter 'timeout' must be greater than zero")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	busy := false
+	for {
+		details, err := s.BedDetailedStatusContext(ctx, bedID)
+		if err != nil {
+			return 0, fmt.Errorf("could not wait for the pump - %w", err)
+		}
+
+		state := PumpProgress{
+			ActiveTask:  details.Pump.ActiveTask,
+			SleepNumber: details.Pump.RightSideSleepNumber,
+			Target:      sleepNumber,
+			Elapsed:     time.Since(start),
+		}
+		if side == Left {
+			state.SleepNumber = details.Pump.LeftSideSleepNumber
+		}
+
+		if progress != nil {
+			progress(state)
+		}
+
+		if state.ActiveTask != 0 {
+			busy = true
+		} else if busy || state.SleepNumber == sleepNumber {
+			break
+		}
+
+		timer := time.NewTimer(pumpPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return state.SleepNumber, fmt.Errorf("pump has not finished adjusting to %d - %w", sleepNumber, ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	// The family status has the sleep number the chamber settled at
+	status, err := s.findBedStatus(ctx, bedID)
+	if err != nil {
+		return 0, fmt.Errorf("unable to retrieve final sleep number - %w", err)
+	}
+
+	return status.Side(side).SleepNumber, nil
+}
+
 // ControlPumpForceIdle forces the pump to be idle
-func (s SleepIQ) ControlPumpForceIdle(bedID string) error {
+func (s *SleepIQ) ControlPumpForceIdle(bedID string) error {
+	return s.ControlPumpForceIdleContext(context.Background(), bedID)
+}
+
+// ControlPumpForceIdleContext is like ControlPumpForceIdle but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) ControlPumpForceIdleContext(ctx context.Context, bedID string) error {
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return ErrNotLoggedIn
 	}
 
 	// Make request - First we need to set the system status
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/bed/{{bedId}}/pump/forceIdle?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/pump/forceIdle?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{bedId}}", bedID, -1)
 
-	responseBytes, _, err := httpPut(url, []byte(""), s.cookies)
+	responseBytes, _, err := s.httpPut(ctx, url, []byte(""))
 	if err != nil {
-		return fmt.Errorf("unable to set pump to idle - %s", err)
+		return fmt.Errorf("unable to set pump to idle - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	var controlResponse controlResponse
 	err = json.Unmarshal(responseBytes, &controlResponse)
 	if err != nil {
-		return fmt.Errorf("could not read control response - %s", err)
+		return fmt.Errorf("could not read control response - %w", err)
 	}
 
-	// Check for an error returned from the service
-	if controlResponse.Error.Code > 0 {
-		return fmt.Errorf("error #%d: %s", controlResponse.Error.Code, controlResponse.Error.Message)
+	return nil
+}
+
+// ============================================================================
+// PRIVACY MODE
+// ============================================================================
+
+// SetBedPrivacyMode turns privacy mode of the bed on or off and returns the
+// refreshed privacy mode. The bed does not collect sleep data while privacy
+// mode is on.
+func (s *SleepIQ) SetBedPrivacyMode(bedID string, on bool) (BedPrivacyModeDetails, error) {
+	return s.SetBedPrivacyModeContext(context.Background(), bedID, on)
+}
+
+// SetBedPrivacyModeContext is like SetBedPrivacyMode but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) SetBedPrivacyModeContext(ctx context.Context, bedID string, on bool) (BedPrivacyModeDetails, error) {
+	// Bail if there is not an active logged-in session
+	if !s.auth.loggedIn() {
+		return BedPrivacyModeDetails{}, ErrNotLoggedIn
 	}
 
-	return nil
+	mode := "off"
+	if on {
+		mode = "on"
+	}
+
+	// Make request
+	url := strings.Replace(s.baseURL+"/bed/{{bedId}}/pauseMode?_k={{key}}&mode={{mode}}", "{{key}}", s.auth.key(), -1)
+	url = strings.Replace(url, "{{bedId}}", bedID, -1)
+	url = strings.Replace(url, "{{mode}}", mode, -1)
+
+	responseBytes, _, err := s.httpPut(ctx, url, []byte(""))
+	if err != nil {
+		return BedPrivacyModeDetails{}, fmt.Errorf("unable to set bed pause mode - %w", err)
+	}
+
+	// Marshal the response to a loginResponse object
+	var controlResponse controlResponse
+	err = json.Unmarshal(responseBytes, &controlResponse)
+	if err != nil {
+		return BedPrivacyModeDetails{}, fmt.Errorf("could not read control response - %w", err)
+	}
+
+	return s.BedPrivacyModeContext(ctx, bedID)
 }
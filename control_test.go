@@ -1,14 +1,20 @@
 package sleepiq
 
 import (
-	"os"
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
 )
 
 func TestControlSuccess(t *testing.T) {
-	siq := New()
+	server, siq := newTestServer()
+	defer server.Close()
 
-	response, err := siq.Login(os.Getenv("sleepiq_username"), os.Getenv("sleepiq_password"))
+	response, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
 	if err != nil {
 		t.Error("login failed - expected success", err)
 		return
@@ -34,13 +40,13 @@ func TestControlSuccess(t *testing.T) {
 	// Test ControlFootWarmer()
 	duration := 3
 	temp := TempLow
-	footWarmer, err := siq.ControlFootWarmer(beds.Beds[0].BedID, "left", temp, duration)
+	footWarmer, err := siq.ControlFootWarmer(beds.Beds[0].BedID, Left, temp, duration)
 	if err != nil {
 		t.Errorf("could not set bed foot warmer - %s", err)
 		return
 	}
 
-	if footWarmer.FootWarmingStatusLeft != temp {
+	if footWarmer.Decode().Left.Level != temp {
 		t.Errorf("failed to verify foot warmer temperature control. Expect=%d, Actual=%d", temp, footWarmer.FootWarmingStatusLeft)
 		return
 	}
@@ -57,7 +63,7 @@ func TestControlSuccess(t *testing.T) {
 	}
 
 	// Test ControlBedPosition()
-	_, err = siq.ControlBedPosition(beds.Beds[0].BedID, "Right", PositionFlat)
+	_, err = siq.ControlBedPosition(beds.Beds[0].BedID, Right, PositionFlat)
 	if err != nil {
 		t.Errorf("could not set bed position - %s", err)
 		return
@@ -98,9 +104,648 @@ func TestControlSuccess(t *testing.T) {
 	}
 
 	// Test ControlSleepNumber()
-	err = siq.ControlSleepNumber(beds.Beds[0].BedID, "Left", 55)
+	err = siq.ControlSleepNumber(beds.Beds[0].BedID, Left, 55)
 	if err != nil {
 		t.Errorf("could not set sleep number - %s", err)
 		return
 	}
 }
+
+func TestControlActuatorPosition(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	err = siq.ControlActuatorPosition(sleepiqtest.DefaultBedID, Left, ActuatorHead, 35)
+	if err != nil {
+		t.Fatalf("could not set actuator position - %s", err)
+	}
+
+	err = siq.ControlActuatorPosition(sleepiqtest.DefaultBedID, Left, ActuatorFoot, 12)
+	if err != nil {
+		t.Fatalf("could not set actuator position - %s", err)
+	}
+
+	status, err := siq.BedFoundationStatus(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not get bed foundation status - %s", err)
+	}
+
+	if status.LeftHeadPosition != "0x23" || status.LeftFootPosition != "0x0c" {
+		t.Errorf("failed to verify actuator positions. Expected=0x23/0x0c, Actual=%s/%s", status.LeftHeadPosition, status.LeftFootPosition)
+	}
+}
+
+func TestControlActuatorPositionInvalidParameters(t *testing.T) {
+	siq := New()
+
+	err := siq.ControlActuatorPosition("bed", Side(2), ActuatorHead, 10)
+	if !errors.Is(err, ErrInvalidSide) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidSide, err)
+	}
+
+	err = siq.ControlActuatorPosition("bed", Left, "X", 10)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+
+	err = siq.ControlActuatorPosition("bed", Left, ActuatorFoot, 101)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestControlStopMotion(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.IsMoving = true
+	})
+
+	err = siq.ControlStopMotion(sleepiqtest.DefaultBedID, Right)
+	if err != nil {
+		t.Fatalf("could not stop bed motion - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.IsMoving {
+		t.Error("bed should have stopped moving")
+	}
+}
+
+func TestWaitForFoundation(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	defer func(interval time.Duration) { foundationPollInterval = interval }(foundationPollInterval)
+	foundationPollInterval = 10 * time.Millisecond
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.IsMoving = true
+	})
+
+	// The bed keeps moving past the timeout
+	_, err = siq.WaitForFoundation(sleepiqtest.DefaultBedID, 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", context.DeadlineExceeded, err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+			bed.IsMoving = false
+		})
+	}()
+
+	status, err := siq.WaitForFoundation(sleepiqtest.DefaultBedID, 5*time.Second)
+	if err != nil {
+		t.Fatalf("could not wait for the bed to stop moving - %s", err)
+	}
+
+	if status.IsMoving {
+		t.Error("bed should have stopped moving")
+	}
+
+	_, err = siq.WaitForFoundation(sleepiqtest.DefaultBedID, 0)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestControlBedPositionTimer(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	status, err := siq.ControlBedPositionTimer(sleepiqtest.DefaultBedID, Right, PositionWatchTV, 30)
+	if err != nil {
+		t.Fatalf("could not set timed bed position - %s", err)
+	}
+
+	if status.TimerPositionPresetRight != "Watch TV" {
+		t.Errorf("failed to verify timed preset. Expected=%s, Actual=%s", "Watch TV", status.TimerPositionPresetRight)
+	}
+
+	timer, err := status.RightPositionTimer()
+	if err != nil {
+		t.Fatalf("could not decode the position timer - %s", err)
+	}
+
+	if timer != 30*time.Minute {
+		t.Errorf("failed to verify position timer. Expected=%s, Actual=%s", 30*time.Minute, timer)
+	}
+
+	_, err = siq.ControlBedPositionTimer(sleepiqtest.DefaultBedID, Right, PositionWatchTV, 0)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestControlResponsiveAir(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	enabled := false
+	timeout := 30
+	settings, err := siq.ControlResponsiveAir(sleepiqtest.DefaultBedID, ResponsiveAirUpdate{
+		LeftSideEnabled: &enabled,
+		InBedTimeout:    &timeout,
+	})
+	if err != nil {
+		t.Fatalf("could not set responsive air - %s", err)
+	}
+
+	if settings.LeftSideEnabled || !settings.RightSideEnabled {
+		t.Errorf("failed to verify responsive air sides. Expected=false/true, Actual=%t/%t", settings.LeftSideEnabled, settings.RightSideEnabled)
+	}
+
+	if settings.InBedTimeout != timeout || settings.OutOfBedTimeout != 60 {
+		t.Errorf("failed to verify responsive air timeouts. Expected=%d/%d, Actual=%d/%d", timeout, 60, settings.InBedTimeout, settings.OutOfBedTimeout)
+	}
+
+	_, err = siq.ControlResponsiveAir(sleepiqtest.DefaultBedID, ResponsiveAirUpdate{})
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestControlResponsiveAirMode(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	err = siq.ControlResponsiveAirMode(sleepiqtest.DefaultBedID, false)
+	if err != nil {
+		t.Fatalf("could not set responsive air mode - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.ResponsiveAir || bed.Right.ResponsiveAir {
+		t.Error("responsive air should be disabled on both sides")
+	}
+}
+
+func TestControlUnderbedLightSide(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	err = siq.ControlUnderbedLightSide(sleepiqtest.DefaultBedID, Left, 42, 0)
+	if err != nil {
+		t.Fatalf("could not set bed light - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.UnderbedLightPWM != 42 || bed.Right.UnderbedLightPWM != 30 {
+		t.Errorf("failed to verify light levels. Expected=%d/%d, Actual=%d/%d", 42, 30, bed.Left.UnderbedLightPWM, bed.Right.UnderbedLightPWM)
+	}
+
+	if !bed.Outlets[OutletLeftUnderbedLight-1].On || bed.Outlets[OutletRightUnderbedLight-1].On {
+		t.Error("only the left light should be on")
+	}
+
+	err = siq.ControlUnderbedLightSideOff(sleepiqtest.DefaultBedID, Left)
+	if err != nil {
+		t.Fatalf("could not turn bed light off - %s", err)
+	}
+
+	bed, _ = server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Outlets[OutletLeftUnderbedLight-1].On {
+		t.Error("left light should be off")
+	}
+
+	err = siq.ControlUnderbedLightSide(sleepiqtest.DefaultBedID, Left, 0, 0)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestControlUnderbedLightOff(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	err = siq.ControlUnderbedLight(sleepiqtest.DefaultBedID, 75, 10)
+	if err != nil {
+		t.Fatalf("could not set bed light - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if !bed.Outlets[OutletLeftUnderbedLight-1].On || !bed.Outlets[OutletRightUnderbedLight-1].On {
+		t.Error("both lights should be on")
+	}
+
+	err = siq.ControlUnderbedLightOff(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not turn bed light off - %s", err)
+	}
+
+	bed, _ = server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Outlets[OutletLeftUnderbedLight-1].On || bed.Outlets[OutletRightUnderbedLight-1].On {
+		t.Error("both lights should be off")
+	}
+}
+
+func TestControlOutlet(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	err = siq.ControlOutlet(sleepiqtest.DefaultBedID, OutletLeftPlug, true, 45)
+	if err != nil {
+		t.Fatalf("could not turn outlet on - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	outlet := bed.Outlets[OutletLeftPlug-1]
+	if !outlet.On || outlet.Timer != 45 || bed.Outlets[OutletRightPlug-1].On {
+		t.Errorf("only the left outlet should be on. Expected=%t/%d, Actual=%t/%d", true, 45, outlet.On, outlet.Timer)
+	}
+
+	err = siq.ControlOutlet(sleepiqtest.DefaultBedID, OutletLeftPlug, false, 0)
+	if err != nil {
+		t.Fatalf("could not turn outlet off - %s", err)
+	}
+
+	bed, _ = server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Outlets[OutletLeftPlug-1].On {
+		t.Error("left outlet should be off")
+	}
+
+	for _, outletID := range []int{0, OutletRightUnderbedLight} {
+		err = siq.ControlOutlet(sleepiqtest.DefaultBedID, outletID, true, 0)
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("unexpected error for outlet %d. Expected=%v, Actual=%v", outletID, ErrInvalidParameter, err)
+		}
+	}
+
+	err = siq.ControlOutlet(sleepiqtest.DefaultBedID, OutletRightPlug, true, 181)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestControlSleepNumberFavorite(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	err = siq.ControlSleepNumberFavorite(sleepiqtest.DefaultBedID, Left, 35)
+	if err != nil {
+		t.Fatalf("could not set sleep number favorite - %s", err)
+	}
+
+	favorite, err := siq.BedSleepNumberFavorite(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not get sleep number favorite - %s", err)
+	}
+
+	if favorite.SleepNumberFavoriteLeft != 35 || favorite.SleepNumberFavoriteRight != 50 {
+		t.Errorf("unexpected sleep number favorite. Expected=%d/%d, Actual=%d/%d", 35, 50, favorite.SleepNumberFavoriteLeft, favorite.SleepNumberFavoriteRight)
+	}
+
+	err = siq.ControlSleepNumberToFavorite(sleepiqtest.DefaultBedID, Left)
+	if err != nil {
+		t.Fatalf("could not restore sleep number favorite - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.SleepNumber != 35 {
+		t.Errorf("sleep number was not restored. Expected=%d, Actual=%d", 35, bed.Left.SleepNumber)
+	}
+
+	err = siq.ControlSleepNumberFavorite(sleepiqtest.DefaultBedID, Left, 101)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestControlSleepNumberRamp(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	err = siq.ControlSleepNumberRamp(sleepiqtest.DefaultBedID, Right, 62, 0)
+	if err != nil {
+		t.Fatalf("could not ramp sleep number - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Right.SleepNumber != 62 || bed.Left.SleepNumber != 50 {
+		t.Errorf("sleep number was not ramped. Expected=%d/%d, Actual=%d/%d", 50, 62, bed.Left.SleepNumber, bed.Right.SleepNumber)
+	}
+
+	// The ramp stops when the context is done before the next step
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = siq.ControlSleepNumberRampContext(ctx, sleepiqtest.DefaultBedID, Right, 32, time.Hour)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", context.DeadlineExceeded, err)
+	}
+
+	bed, _ = server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Right.SleepNumber != 57 {
+		t.Errorf("only the first step should have been made. Expected=%d, Actual=%d", 57, bed.Right.SleepNumber)
+	}
+}
+
+func TestControlSleepNumberRampWaitsForPump(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	defer func(interval time.Duration) { pumpPollInterval = interval }(pumpPollInterval)
+	pumpPollInterval = 5 * time.Millisecond
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	// Each step would be abandoned if the next one was sent before the pump finished
+	server.SetPumpDelay(20 * time.Millisecond)
+
+	err = siq.ControlSleepNumberRamp(sleepiqtest.DefaultBedID, Left, 38, 0)
+	if err != nil {
+		t.Fatalf("could not ramp sleep number - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.SleepNumber != 38 || bed.PumpActiveTask != 0 {
+		t.Errorf("ramp did not settle. Expected=%d, Actual=%d, ActiveTask=%d", 38, bed.Left.SleepNumber, bed.PumpActiveTask)
+	}
+}
+
+func TestSleepNumberSteps(t *testing.T) {
+	tests := []struct {
+		from     int
+		to       int
+		expected string
+	}{
+		{50, 50, "[]"},
+		{50, 62, "[55 60 62]"},
+		{50, 35, "[45 40 35]"},
+		{50, 53, "[53]"},
+	}
+
+	for _, test := range tests {
+		actual := fmt.Sprint(sleepNumberSteps(test.from, test.to))
+		if actual != test.expected {
+			t.Errorf("unexpected steps from %d to %d. Expected=%s, Actual=%s", test.from, test.to, test.expected, actual)
+		}
+	}
+}
+
+func TestControlSleepNumberAndWait(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	defer func(interval time.Duration) { pumpPollInterval = interval }(pumpPollInterval)
+	pumpPollInterval = 10 * time.Millisecond
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	// The pump is idle but the side never reaches the target
+	_, err = siq.WaitForPump(sleepiqtest.DefaultBedID, Left, 40, 50*time.Millisecond, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", context.DeadlineExceeded, err)
+	}
+
+	// The pump is still idle on the first polls after the change is sent
+	server.SetPumpDelay(50 * time.Millisecond)
+
+	var updates []PumpProgress
+	progress := func(state PumpProgress) {
+		updates = append(updates, state)
+	}
+
+	sleepNumber, err := siq.ControlSleepNumberAndWait(sleepiqtest.DefaultBedID, Left, 40, 5*time.Second, progress)
+	if err != nil {
+		t.Fatalf("could not wait for the pump - %s", err)
+	}
+
+	if sleepNumber != 40 {
+		t.Errorf("unexpected final sleep number. Expected=%d, Actual=%d", 40, sleepNumber)
+	}
+
+	if len(updates) < 3 || updates[0].ActiveTask != 0 || updates[0].SleepNumber != 50 {
+		t.Fatalf("wait should continue while the pump has not started. Actual=%+v", updates)
+	}
+
+	busy := false
+	for _, update := range updates {
+		busy = busy || update.ActiveTask != 0
+		if update.Target != 40 {
+			t.Errorf("unexpected target. Expected=%d, Actual=%d", 40, update.Target)
+		}
+	}
+
+	last := updates[len(updates)-1]
+	if !busy || last.ActiveTask != 0 || last.SleepNumber != 40 {
+		t.Errorf("unexpected progress updates. Actual=%+v", updates)
+	}
+}
+
+func TestWaitForPumpSettlesElsewhere(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	defer func(interval time.Duration) { pumpPollInterval = interval }(pumpPollInterval)
+	pumpPollInterval = 10 * time.Millisecond
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.PumpActiveTask = 1
+	})
+
+	// The sleeper changes the sleep number from the remote while the pump is busy
+	updates := 0
+	progress := func(state PumpProgress) {
+		updates++
+		if updates == 2 {
+			server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+				bed.PumpActiveTask = 0
+				bed.Left.SleepNumber = 45
+			})
+		}
+	}
+
+	sleepNumber, err := siq.WaitForPump(sleepiqtest.DefaultBedID, Left, 40, 5*time.Second, progress)
+	if err != nil {
+		t.Fatalf("could not wait for the pump - %s", err)
+	}
+
+	if sleepNumber != 45 || updates != 3 {
+		t.Errorf("wait should end when the pump finishes. Expected=%d, Actual=%d after %d polls", 45, sleepNumber, updates)
+	}
+
+	_, err = siq.WaitForPump(sleepiqtest.DefaultBedID, Left, 40, 0, nil)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+}
+
+func TestSetBedPrivacyMode(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	privacyMode, err := siq.SetBedPrivacyMode(sleepiqtest.DefaultBedID, true)
+	if err != nil {
+		t.Fatalf("could not turn privacy mode on - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if !privacyMode.Enabled() || privacyMode.PauseMode != "on" || !bed.PauseMode {
+		t.Errorf("privacy mode was not turned on. Expected=%s, Actual=%s", "on", privacyMode.PauseMode)
+	}
+
+	privacyMode, err = siq.SetBedPrivacyMode(sleepiqtest.DefaultBedID, false)
+	if err != nil {
+		t.Fatalf("could not turn privacy mode off - %s", err)
+	}
+
+	bed, _ = server.Bed(sleepiqtest.DefaultBedID)
+	if privacyMode.Enabled() || bed.PauseMode {
+		t.Errorf("privacy mode was not turned off. Expected=%s, Actual=%s", "off", privacyMode.PauseMode)
+	}
+}
+
+func TestControlFootWarmers(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	status, err := siq.ControlFootWarmers(sleepiqtest.DefaultBedID, FootWarmerUpdate{
+		Left:  &FootWarmerSetting{Level: TempHigh, Duration: 30},
+		Right: &FootWarmerSetting{Level: TempLow, Duration: 120},
+	})
+	if err != nil {
+		t.Fatalf("could not set foot warmers - %s", err)
+	}
+
+	state := status.Decode()
+	expected := FootWarmerState{
+		Left:  FootWarmerSide{Level: TempHigh, Remaining: 30 * time.Minute},
+		Right: FootWarmerSide{Level: TempLow, Remaining: 2 * time.Hour},
+	}
+	if state != expected {
+		t.Errorf("unexpected foot warmer state. Expected=%+v, Actual=%+v", expected, state)
+	}
+
+	// Only the right side is changed
+	status, err = siq.ControlFootWarmers(sleepiqtest.DefaultBedID, FootWarmerUpdate{
+		Right: &FootWarmerSetting{Level: TempOff},
+	})
+	if err != nil {
+		t.Fatalf("could not turn the right foot warmer off - %s", err)
+	}
+
+	state = status.Decode()
+	if state.Left.Level != TempHigh || state.Right.Level != TempOff {
+		t.Errorf("unexpected foot warmer levels. Expected=%s/%s, Actual=%s/%s", TempHigh, TempOff, state.Left.Level, state.Right.Level)
+	}
+
+	status, err = siq.ControlFootWarmerOff(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not turn foot warmers off - %s", err)
+	}
+
+	state = status.Decode()
+	if state.Left.Level != TempOff || state.Right.Level != TempOff {
+		t.Errorf("foot warmers should be off. Actual=%s/%s", state.Left.Level, state.Right.Level)
+	}
+
+	// A single side follows the same rules as an update of both sides
+	status, err = siq.ControlFootWarmer(sleepiqtest.DefaultBedID, Left, TempOff, 0)
+	if err != nil || status.Decode().Left.Level != TempOff {
+		t.Errorf("could not turn the left foot warmer off without a duration - %v", err)
+	}
+
+	_, err = siq.ControlFootWarmer(sleepiqtest.DefaultBedID, Left, TempHigh, 0)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidParameter, err)
+	}
+
+	invalid := []FootWarmerUpdate{
+		{},
+		{Left: &FootWarmerSetting{Level: 40, Duration: 30}},
+		{Right: &FootWarmerSetting{Level: TempMedium}},
+		{Right: &FootWarmerSetting{Level: TempMedium, Duration: 361}},
+	}
+	for _, update := range invalid {
+		_, err = siq.ControlFootWarmers(sleepiqtest.DefaultBedID, update)
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("unexpected error for %+v. Expected=%v, Actual=%v", update, ErrInvalidParameter, err)
+		}
+	}
+}
+
+func TestFootWarmerLevelString(t *testing.T) {
+	if TempMedium.String() != "Medium" || FootWarmerLevel(40).String() != "FootWarmerLevel(40)" {
+		t.Errorf("unexpected foot warmer level names. Actual=%s/%s", TempMedium, FootWarmerLevel(40))
+	}
+}
@@ -0,0 +1,95 @@
+package sleepiq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors returned by the package. Use errors.Is to test for them as they are
+// usually wrapped with additional context.
+var (
+	ErrNotLoggedIn        = errors.New("user is not logged-in. Please login and try again")
+	ErrSessionExpired     = errors.New("session has expired. Please login and try again")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidSession     = errors.New("session is invalid")
+	ErrBedOffline         = errors.New("bed is offline")
+	ErrRateLimited        = errors.New("too many requests")
+	ErrInvalidParameter   = errors.New("invalid parameter")
+	ErrInvalidSide        = invalidParameter("parameter 'side' must be 'left' or 'right'")
+
+	// ErrFeatureNotSupported is returned by control methods when the bed
+	// does not have the feature being controlled
+	ErrFeatureNotSupported = errors.New("feature is not supported by the bed")
+)
+
+// Service error codes with a known meaning
+const (
+	errorCodeSessionInvalid = 50002
+)
+
+// APIError is returned when the SleepIQ service rejects a request. It
+// carries the HTTP status code and the error reported by the service.
+type APIError struct {
+	StatusCode int
+	ServiceError
+
+	// err classifies the failure as one of the package errors, if known
+	err error
+}
+
+// newAPIError creates an APIError and classifies it based on the status
+// code and the service error
+func newAPIError(statusCode int, serviceError ServiceError) *APIError {
+	e := &APIError{
+		StatusCode:   statusCode,
+		ServiceError: serviceError,
+	}
+
+	switch {
+	case statusCode == http.StatusUnauthorized || serviceError.Code == errorCodeSessionInvalid:
+		e.err = ErrSessionExpired
+	case statusCode == http.StatusTooManyRequests:
+		e.err = ErrRateLimited
+	case strings.Contains(strings.ToLower(serviceError.Message), "offline"):
+		e.err = ErrBedOffline
+	}
+
+	return e
+}
+
+// Error formats the service error
+func (e *APIError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("http status %d: %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("error #%d: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the package error that classifies the failure, allowing
+// errors.Is(err, ErrSessionExpired) and similar checks
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// parameterError describes an invalid argument passed to a method. All
+// parameter errors match ErrInvalidParameter.
+type parameterError struct {
+	message string
+}
+
+// invalidParameter creates a new parameter error with the given message
+func invalidParameter(message string) error {
+	return &parameterError{message: message}
+}
+
+// Error returns the description of the invalid parameter
+func (e *parameterError) Error() string {
+	return e.message
+}
+
+// Is reports whether the target is ErrInvalidParameter
+func (e *parameterError) Is(target error) bool {
+	return target == ErrInvalidParameter
+}
@@ -0,0 +1,84 @@
+package sleepiq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorSessionExpired(t *testing.T) {
+	err := fmt.Errorf("unable to retrieve bed details - %w", newAPIError(http.StatusUnauthorized, ServiceError{Code: errorCodeSessionInvalid, Message: "Session is invalid"}))
+
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("error was not classified as an expired session - %s", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Error("error could not be converted to an APIError")
+		return
+	}
+
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Code != errorCodeSessionInvalid {
+		t.Errorf("unexpected APIError details. StatusCode=%d, Code=%d", apiErr.StatusCode, apiErr.Code)
+	}
+}
+
+func TestAPIErrorUnclassified(t *testing.T) {
+	err := newAPIError(http.StatusInternalServerError, ServiceError{})
+
+	if errors.Is(err, ErrSessionExpired) || errors.Is(err, ErrBedOffline) {
+		t.Errorf("error should not have been classified - %s", err)
+	}
+
+	if err.Error() != "http status 500: Internal Server Error" {
+		t.Errorf("unexpected error message. Actual=%s", err)
+	}
+}
+
+func TestInvalidSideIsInvalidParameter(t *testing.T) {
+	siq := New()
+	_, err := siq.ControlBedPosition("bed", Side(2), PositionFlat)
+
+	if !errors.Is(err, ErrInvalidSide) {
+		t.Errorf("expected ErrInvalidSide. Actual=%v", err)
+	}
+
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter. Actual=%v", err)
+	}
+}
+
+func TestNotLoggedIn(t *testing.T) {
+	siq := New()
+	_, err := siq.Beds()
+
+	if !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("expected ErrNotLoggedIn. Actual=%v", err)
+	}
+}
+
+func TestLoginInvalidCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"Error":{"Code":401,"Message":"Incorrect username or password"}}`))
+	}))
+	defer server.Close()
+
+	siq := New(WithBaseURL(server.URL))
+	response, err := siq.Login("JohnDoe@live.com", "bogusPassword")
+
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials. Actual=%v", err)
+	}
+
+	if errors.Is(err, ErrSessionExpired) {
+		t.Errorf("a failed login should not be reported as an expired session - %s", err)
+	}
+
+	if response.Error.Code != 401 {
+		t.Errorf("service error was not returned in the response. Expected=%d, Actual=%d", 401, response.Error.Code)
+	}
+}
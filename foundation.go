@@ -0,0 +1,192 @@
+package sleepiq
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// DECODED FOUNDATION STATUS
+// ============================================================================
+
+// FoundationState is the decoded form of BedFoundationStatus
+type FoundationState struct {
+	Type           string
+	Configured     bool
+	NeedsHoming    bool
+	IsMoving       bool
+	OutletsOn      bool
+	TimedOutletsOn bool
+	Summary        FoundationSummary
+	Left           FoundationSide
+	Right          FoundationSide
+}
+
+// FoundationSide is the decoded state of one side of the foundation
+type FoundationSide struct {
+	Preset       int           // Position* constant of the current preset or zero when the bed is not at a preset
+	TimedPreset  int           // Position* constant of the timed preset or zero when no timer is running
+	Timer        time.Duration // Time left before the side returns to flat
+	HeadPosition int           // Head actuator position between 0 and 100
+	FootPosition int           // Foot actuator position between 0 and 100
+	HeadMotor    MotorStatus
+	FootMotor    MotorStatus
+}
+
+// MotorStatus is the state of a foundation actuator motor
+type MotorStatus int
+
+// Motor states. Other values reported by the foundation are kept as is.
+const (
+	MotorIdle       MotorStatus = 0x00
+	MotorMovingUp   MotorStatus = 0x01
+	MotorMovingDown MotorStatus = 0x02
+)
+
+// String returns the name of the motor status
+func (m MotorStatus) String() string {
+	switch m {
+	case MotorIdle:
+		return "Idle"
+	case MotorMovingUp:
+		return "MovingUp"
+	case MotorMovingDown:
+		return "MovingDown"
+	}
+	return fmt.Sprintf("MotorStatus(0x%02x)", int(m))
+}
+
+// FoundationSummary holds the bit flags of the foundation status summary
+type FoundationSummary int
+
+// Foundation status summary flags
+const (
+	SummaryMoving         FoundationSummary = 0x01
+	SummaryLeftTimer      FoundationSummary = 0x02
+	SummaryRightTimer     FoundationSummary = 0x04
+	SummaryOutletsOn      FoundationSummary = 0x08
+	SummaryTimedOutletsOn FoundationSummary = 0x10
+	SummaryNeedsHoming    FoundationSummary = 0x20
+	SummaryPinchDetected  FoundationSummary = 0x40
+	SummaryBoardFault     FoundationSummary = 0x80
+)
+
+// foundationSummaryNames holds the names of the summary flags by bit
+var foundationSummaryNames = []string{
+	"Moving",
+	"LeftTimer",
+	"RightTimer",
+	"OutletsOn",
+	"TimedOutletsOn",
+	"NeedsHoming",
+	"PinchDetected",
+	"BoardFault",
+}
+
+// Has reports whether all of the given flags are set
+func (f FoundationSummary) Has(flags FoundationSummary) bool {
+	return f&flags == flags
+}
+
+// String lists the names of the flags that are set
+func (f FoundationSummary) String() string {
+	var names []string
+	for bit, name := range foundationSummaryNames {
+		if f&(1<<bit) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, "|")
+}
+
+// foundationPresets maps the preset names reported by the foundation to the
+// Position* constants
+var foundationPresets = map[string]int{
+	"not at preset": 0,
+	"favorite":      PositionFavorite,
+	"read":          PositionRead,
+	"watch tv":      PositionWatchTV,
+	"flat":          PositionFlat,
+	"zero g":        PositionZeroG,
+	"snore":         PositionSnore,
+}
+
+// Decode converts the raw values reported by the foundation into typed
+// values. Presets that are not known to the library are decoded as zero.
+func (f BedFoundationStatus) Decode() (FoundationState, error) {
+	state := FoundationState{
+		Type:           f.Type,
+		Configured:     f.Configured,
+		NeedsHoming:    f.NeedsHoming,
+		IsMoving:       f.IsMoving,
+		OutletsOn:      f.OutletsOn,
+		TimedOutletsOn: f.TimedOutletsOn,
+	}
+
+	summary, err := parseHexByte(f.StatusSummary)
+	if err != nil {
+		return state, fmt.Errorf("could not read status summary - %w", err)
+	}
+	state.Summary = FoundationSummary(summary)
+
+	state.Left, err = decodeFoundationSide(f.CurrentPositionPresetLeft, f.TimerPositionPresetLeft,
+		f.LeftPositionTimerMSB, f.LeftPositionTimerLSB,
+		f.LeftHeadPosition, f.LeftFootPosition,
+		f.LeftHeadActuatorMotorStatus, f.LeftFootActuatorMotorStatus)
+	if err != nil {
+		return state, fmt.Errorf("could not read left side - %w", err)
+	}
+
+	state.Right, err = decodeFoundationSide(f.CurrentPositionPresetRight, f.TimerPositionPresetRight,
+		f.RightPositionTimerMSB, f.RightPositionTimerLSB,
+		f.RightHeadPosition, f.RightFootPosition,
+		f.RightHeadActuatorMotorStatus, f.RightFootActuatorMotorStatus)
+	if err != nil {
+		return state, fmt.Errorf("could not read right side - %w", err)
+	}
+
+	return state, nil
+}
+
+// decodeFoundationSide decodes the raw values of one side of the foundation
+func decodeFoundationSide(preset string, timedPreset string, timerMSB string, timerLSB string, head string, foot string, headMotor string, footMotor string) (FoundationSide, error) {
+	var side FoundationSide
+	var err error
+
+	side.Preset = foundationPresets[strings.ToLower(preset)]
+	side.TimedPreset = foundationPresets[strings.ToLower(timedPreset)]
+
+	side.Timer, err = positionTimer(timerMSB, timerLSB)
+	if err != nil {
+		return side, err
+	}
+
+	side.HeadPosition, err = parseHexByte(head)
+	if err != nil {
+		return side, fmt.Errorf("could not read head position - %w", err)
+	}
+
+	side.FootPosition, err = parseHexByte(foot)
+	if err != nil {
+		return side, fmt.Errorf("could not read foot position - %w", err)
+	}
+
+	motor, err := parseHexByte(headMotor)
+	if err != nil {
+		return side, fmt.Errorf("could not read head motor status - %w", err)
+	}
+	side.HeadMotor = MotorStatus(motor)
+
+	motor, err = parseHexByte(footMotor)
+	if err != nil {
+		return side, fmt.Errorf("could not read foot motor status - %w", err)
+	}
+	side.FootMotor = MotorStatus(motor)
+
+	return side, nil
+}
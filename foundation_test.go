@@ -0,0 +1,97 @@
+package sleepiq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
+)
+
+func TestFoundationStatusDecode(t *testing.T) {
+	status := BedFoundationStatus{
+		CurrentPositionPresetLeft:    "Not at preset",
+		CurrentPositionPresetRight:   "Watch TV",
+		TimerPositionPresetRight:     "Watch TV",
+		RightPositionTimerMSB:        "0x03",
+		RightPositionTimerLSB:        "0x84",
+		LeftHeadPosition:             "0x2d",
+		LeftFootPosition:             "0x0A",
+		RightHeadPosition:            "0x00",
+		RightFootPosition:            "0x64",
+		LeftHeadActuatorMotorStatus:  "0x01",
+		RightFootActuatorMotorStatus: "0x07",
+		StatusSummary:                "0x42",
+		Type:                         "splitKing",
+		IsMoving:                     true,
+	}
+
+	state, err := status.Decode()
+	if err != nil {
+		t.Fatalf("could not decode foundation status - %s", err)
+	}
+
+	if state.Left.Preset != 0 || state.Right.Preset != PositionWatchTV || state.Right.TimedPreset != PositionWatchTV {
+		t.Errorf("failed to decode presets. Expected=0/%d/%d, Actual=%d/%d/%d", PositionWatchTV, PositionWatchTV, state.Left.Preset, state.Right.Preset, state.Right.TimedPreset)
+	}
+
+	if state.Right.Timer != 15*time.Minute {
+		t.Errorf("failed to decode timer. Expected=%s, Actual=%s", 15*time.Minute, state.Right.Timer)
+	}
+
+	if state.Left.HeadPosition != 45 || state.Left.FootPosition != 10 || state.Right.FootPosition != 100 {
+		t.Errorf("failed to decode positions. Expected=45/10/100, Actual=%d/%d/%d", state.Left.HeadPosition, state.Left.FootPosition, state.Right.FootPosition)
+	}
+
+	if state.Left.HeadMotor != MotorMovingUp || state.Left.FootMotor != MotorIdle {
+		t.Errorf("failed to decode motor status. Expected=%s/%s, Actual=%s/%s", MotorMovingUp, MotorIdle, state.Left.HeadMotor, state.Left.FootMotor)
+	}
+
+	if state.Right.FootMotor.String() != "MotorStatus(0x07)" {
+		t.Errorf("failed to format unknown motor status. Expected=%s, Actual=%s", "MotorStatus(0x07)", state.Right.FootMotor)
+	}
+
+	if !state.Summary.Has(SummaryLeftTimer|SummaryPinchDetected) || state.Summary.Has(SummaryMoving) {
+		t.Errorf("failed to decode status summary. Expected=%s, Actual=%s", SummaryLeftTimer|SummaryPinchDetected, state.Summary)
+	}
+
+	if state.Summary.String() != "LeftTimer|PinchDetected" {
+		t.Errorf("failed to format status summary. Expected=%s, Actual=%s", "LeftTimer|PinchDetected", state.Summary)
+	}
+
+	if state.Type != "splitKing" || !state.IsMoving {
+		t.Error("failed to copy foundation flags")
+	}
+}
+
+func TestFoundationStatusDecodeInvalid(t *testing.T) {
+	status := BedFoundationStatus{LeftHeadPosition: "high"}
+
+	_, err := status.Decode()
+	if err == nil {
+		t.Error("decoding an invalid position succeeded - expected failure")
+	}
+}
+
+func TestFoundationStatusDecodeServer(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	status, err := siq.ControlBedPosition(sleepiqtest.DefaultBedID, Left, PositionZeroG)
+	if err != nil {
+		t.Fatalf("could not set bed position - %s", err)
+	}
+
+	state, err := status.Decode()
+	if err != nil {
+		t.Fatalf("could not decode foundation status - %s", err)
+	}
+
+	if state.Left.Preset != PositionZeroG || state.Right.Preset != PositionFlat {
+		t.Errorf("failed to decode presets. Expected=%d/%d, Actual=%d/%d", PositionZeroG, PositionFlat, state.Left.Preset, state.Right.Preset)
+	}
+}
@@ -0,0 +1,213 @@
+package sleepiq
+
+import (
+	"context"
+	"fmt"
+)
+
+// ============================================================================
+// BED HANDLE
+// ============================================================================
+
+// BedHandle gives access to one bed of the account without passing its ID
+// to every call. Handles are cheap to create and hold no state besides the
+// bed ID.
+type BedHandle struct {
+	siq *SleepIQ
+	id  string
+}
+
+// Bed returns a handle for the bed with the given ID. The bed is not looked
+// up until a method of the handle is called.
+func (s *SleepIQ) Bed(bedID string) *BedHandle {
+	return &BedHandle{siq: s, id: bedID}
+}
+
+// ID returns the ID of the bed
+func (b *BedHandle) ID() string {
+	return b.id
+}
+
+// Left returns a handle for the left side of the bed
+func (b *BedHandle) Left() *SideHandle {
+	return &SideHandle{bed: b, side: Left}
+}
+
+// Right returns a handle for the right side of the bed
+func (b *BedHandle) Right() *SideHandle {
+	return &SideHandle{bed: b, side: Right}
+}
+
+// Info gets the details of the bed
+func (b *BedHandle) Info() (Bed, error) {
+	return b.InfoContext(context.Background())
+}
+
+// InfoContext is like Info but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (b *BedHandle) InfoContext(ctx context.Context) (Bed, error) {
+	return b.siq.findBed(ctx, b.id)
+}
+
+// Status gets the occupancy and sleep numbers of both sides of the bed
+func (b *BedHandle) Status() (BedStatus, error) {
+	return b.StatusContext(context.Background())
+}
+
+// StatusContext is like Status but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (b *BedHandle) StatusContext(ctx context.Context) (BedStatus, error) {
+	return b.siq.findBedStatus(ctx, b.id)
+}
+
+// Capabilities gets the features of the bed. See SleepIQ.Capabilities.
+func (b *BedHandle) Capabilities() (Capabilities, error) {
+	return b.CapabilitiesContext(context.Background())
+}
+
+// CapabilitiesContext is like Capabilities but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (b *BedHandle) CapabilitiesContext(ctx context.Context) (Capabilities, error) {
+	return b.siq.CapabilitiesContext(ctx, b.id)
+}
+
+// ============================================================================
+// SIDE HANDLE
+// ============================================================================
+
+// SideHandle gives access to one side of a bed
+type SideHandle struct {
+	bed  *BedHandle
+	side Side
+}
+
+// Bed returns the handle of the bed the side belongs to
+func (h *SideHandle) Bed() *BedHandle {
+	return h.bed
+}
+
+// Side returns the side of the bed
+func (h *SideHandle) Side() Side {
+	return h.side
+}
+
+// SetSleepNumber sets the sleep number of the side
+func (h *SideHandle) SetSleepNumber(sleepNumber int) error {
+	return h.SetSleepNumberContext(context.Background(), sleepNumber)
+}
+
+// SetSleepNumberContext is like SetSleepNumber but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (h *SideHandle) SetSleepNumberContext(ctx context.Context, sleepNumber int) error {
+	return h.bed.siq.ControlSleepNumberContext(ctx, h.bed.id, h.side, sleepNumber)
+}
+
+// SetPosition moves the side to one of the Position* presets
+func (h *SideHandle) SetPosition(position int) (BedFoundationStatus, error) {
+	return h.SetPositionContext(context.Background(), position)
+}
+
+// SetPositionContext is like SetPosition but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (h *SideHandle) SetPositionContext(ctx context.Context, position int) (BedFoundationStatus, error) {
+	return h.bed.siq.ControlBedPositionContext(ctx, h.bed.id, h.side, position)
+}
+
+// FootWarmer sets the foot warmer of the side to the given level for the
+// given number of minutes
+func (h *SideHandle) FootWarmer(level FootWarmerLevel, duration int) (FootWarmingStatus, error) {
+	return h.FootWarmerContext(context.Background(), level, duration)
+}
+
+// FootWarmerContext is like FootWarmer but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (h *SideHandle) FootWarmerContext(ctx context.Context, level FootWarmerLevel, duration int) (FootWarmingStatus, error) {
+	return h.bed.siq.ControlFootWarmerContext(ctx, h.bed.id, h.side, level, duration)
+}
+
+// Status gets the occupancy and sleep number of the side
+func (h *SideHandle) Status() (SideStatus, error) {
+	return h.StatusContext(context.Background())
+}
+
+// StatusContext is like Status but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (h *SideHandle) StatusContext(ctx context.Context) (SideStatus, error) {
+	status, err := h.bed.siq.findBedStatus(ctx, h.bed.id)
+	if err != nil {
+		return SideStatus{}, err
+	}
+
+	return status.Side(h.side), nil
+}
+
+// Sleeper gets the sleeper assigned to the side. An error is returned when
+// no sleeper is assigned.
+func (h *SideHandle) Sleeper() (Sleeper, error) {
+	return h.SleeperContext(context.Background())
+}
+
+// SleeperContext is like Sleeper but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (h *SideHandle) SleeperContext(ctx context.Context) (Sleeper, error) {
+	bed, err := h.bed.siq.findBed(ctx, h.bed.id)
+	if err != nil {
+		return Sleeper{}, err
+	}
+
+	sleeperID := bed.SleeperRightID
+	if h.side == Left {
+		sleeperID = bed.SleeperLeftID
+	}
+	if sleeperID == "" || sleeperID == "0" {
+		return Sleeper{}, fmt.Errorf("no sleeper is assigned to the %s side of bed %s", h.side, h.bed.id)
+	}
+
+	sleepers, err := h.bed.siq.SleepersContext(ctx)
+	if err != nil {
+		return Sleeper{}, err
+	}
+
+	for _, sleeper := range sleepers.Sleepers {
+		if sleeper.SleeperID == sleeperID {
+			return sleeper, nil
+		}
+	}
+
+	return Sleeper{}, fmt.Errorf("sleeper %s was not found in the account", sleeperID)
+}
+
+// ============================================================================
+// SUPPORTING FUNCTIONS
+// ============================================================================
+
+// findBed gets the details of a bed of the account
+func (s *SleepIQ) findBed(ctx context.Context, bedID string) (Bed, error) {
+	beds, err := s.BedsContext(ctx)
+	if err != nil {
+		return Bed{}, err
+	}
+
+	for _, bed := range beds.Beds {
+		if bed.BedID == bedID {
+			return bed, nil
+		}
+	}
+
+	return Bed{}, fmt.Errorf("bed %s was not found in the account", bedID)
+}
+
+// findBedStatus gets the family status of a bed of the account
+func (s *SleepIQ) findBedStatus(ctx context.Context, bedID string) (BedStatus, error) {
+	status, err := s.BedFamilyStatusContext(ctx)
+	if err != nil {
+		return BedStatus{}, err
+	}
+
+	bed, ok := status.Bed(bedID)
+	if !ok {
+		return bed, fmt.Errorf("bed %s was not found in the family status", bedID)
+	}
+
+	return bed, nil
+}
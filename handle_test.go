@@ -0,0 +1,108 @@
+package sleepiq
+
+import (
+	"testing"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
+)
+
+func TestBedHandle(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	bed := siq.Bed(sleepiqtest.DefaultBedID)
+	if bed.ID() != sleepiqtest.DefaultBedID || bed.Left().Side() != Left || bed.Right().Bed() != bed {
+		t.Error("unexpected bed handle")
+	}
+
+	info, err := bed.Info()
+	if err != nil || info.BedID != sleepiqtest.DefaultBedID {
+		t.Errorf("could not get bed info. Expected=%s, Actual=%s (%v)", sleepiqtest.DefaultBedID, info.BedID, err)
+	}
+
+	err = bed.Left().SetSleepNumber(35)
+	if err != nil {
+		t.Fatalf("could not set sleep number - %s", err)
+	}
+
+	status, err := bed.Left().Status()
+	if err != nil {
+		t.Fatalf("could not get side status - %s", err)
+	}
+
+	if status.SleepNumber != 35 {
+		t.Errorf("unexpected sleep number. Expected=%d, Actual=%d", 35, status.SleepNumber)
+	}
+
+	bedStatus, err := bed.Status()
+	if err != nil || bedStatus.Side(Right).SleepNumber != 50 {
+		t.Errorf("unexpected bed status. Expected=%d, Actual=%d (%v)", 50, bedStatus.Side(Right).SleepNumber, err)
+	}
+
+	foundation, err := bed.Right().SetPosition(PositionWatchTV)
+	if err != nil {
+		t.Fatalf("could not set position - %s", err)
+	}
+
+	if foundation.CurrentPositionPresetRight != "Watch TV" {
+		t.Errorf("unexpected position. Expected=%s, Actual=%s", "Watch TV", foundation.CurrentPositionPresetRight)
+	}
+
+	footWarmer, err := bed.Right().FootWarmer(TempMedium, 45)
+	if err != nil {
+		t.Fatalf("could not set foot warmer - %s", err)
+	}
+
+	if footWarmer.Decode().Right.Level != TempMedium {
+		t.Errorf("unexpected foot warmer level. Expected=%s, Actual=%s", TempMedium, footWarmer.Decode().Right.Level)
+	}
+
+	footWarmer, err = bed.Right().FootWarmer(TempOff, 0)
+	if err != nil {
+		t.Fatalf("could not turn foot warmer off - %s", err)
+	}
+
+	if footWarmer.Decode().Right.Level != TempOff {
+		t.Errorf("unexpected foot warmer level. Expected=%s, Actual=%s", TempOff, footWarmer.Decode().Right.Level)
+	}
+}
+
+func TestSideHandleSleeper(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	bed := siq.Bed(sleepiqtest.DefaultBedID)
+
+	sleeper, err := bed.Right().Sleeper()
+	if err != nil {
+		t.Fatalf("could not get sleeper - %s", err)
+	}
+
+	if sleeper.SleeperID != sleepiqtest.DefaultRightSleeperID {
+		t.Errorf("unexpected sleeper. Expected=%s, Actual=%s", sleepiqtest.DefaultRightSleeperID, sleeper.SleeperID)
+	}
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.SleeperLeftID = "0"
+	})
+
+	_, err = bed.Left().Sleeper()
+	if err == nil {
+		t.Error("getting the sleeper of an unassigned side succeeded - expected failure")
+	}
+
+	_, err = siq.Bed("unknown").Left().Status()
+	if err == nil {
+		t.Error("getting the status of an unknown bed succeeded - expected failure")
+	}
+}
@@ -2,128 +2,127 @@ package sleepiq
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
-	"time"
 )
 
 // httpGet conducts a GET request with the provided url. The function returns the response from the
 // service as a byte array.
-func httpGet(url string, cookies []*http.Cookie, headers map[string]string) ([]byte, error) {
-	var response []byte
+func (s *SleepIQ) httpGet(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	response, _, err := s.httpDo(ctx, http.MethodGet, url, nil, headers, true)
+	return response, err
+}
 
-	// Create a new http client
-	client := http.Client{
-		Timeout: 20 * time.Second,
+// httpPut conducts a PUT request with the provided url. The function returns the response from the
+// service as a byte array along with the cookies set by the service.
+func (s *SleepIQ) httpPut(ctx context.Context, url string, payload []byte) ([]byte, []*http.Cookie, error) {
+	headers := map[string]string{
+		"Content-Type": "application/json",
 	}
 
-	// Create the request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return response, err
-	}
+	return s.httpDo(ctx, http.MethodPut, url, payload, headers, true)
+}
 
-	// Add headers
-	for key, value := range headers {
-		req.Header.Set(key, value)
+// httpPost conducts a POST request with the provided url. The function returns the response from the
+// service as a byte array.
+func (s *SleepIQ) httpPost(ctx context.Context, url string, payload []byte) ([]byte, error) {
+	headers := map[string]string{
+		"Content-Type":              "application/json",
+		"Ocp-Apim-Subscription-Key": "3c924e14923642baa1c4ad1d5096a1c5",
+		"Accept":                    "application/json, text/javascript, */*; q=0.01",
 	}
 
-	// Add cookies
-	for _, cookie := range cookies {
-		req.AddCookie(cookie)
-	}
+	response, _, err := s.httpDo(ctx, http.MethodPost, url, payload, headers, false)
+	return response, err
+}
 
-	// Make the request
-	res, err := client.Do(req)
-	if err != nil {
-		return response, err
+// httpDo conducts a request and returns the response and the cookies set by the service. Transient
+// failures are retried according to the retry policy. When the request is rejected because the
+// session has expired, the user is logged-in again and the request is replayed once with the new
+// session.
+func (s *SleepIQ) httpDo(ctx context.Context, method string, url string, payload []byte, headers map[string]string, sendCookies bool) ([]byte, []*http.Cookie, error) {
+	response, cookies, err := s.httpRetry(ctx, method, url, payload, headers, sendCookies)
+	if !errors.Is(err, ErrSessionExpired) {
+		return response, cookies, err
 	}
-	defer res.Body.Close()
 
-	// Read the response
-	response, err = ioutil.ReadAll(res.Body)
-	if err != nil {
-		return response, err
+	// Login again and replay the request with the new session. The original error is
+	// returned if the session cannot be renewed.
+	url, loginErr := s.relogin(ctx, url)
+	if loginErr != nil {
+		return response, cookies, err
 	}
 
-	return response, nil
+	return s.httpRetry(ctx, method, url, payload, headers, sendCookies)
 }
 
-// httpPut conducts a PUT request with the provided url. The function returns the response from the
-// service as a byte array.
-func httpPut(url string, payload []byte, cookies []*http.Cookie) ([]byte, []*http.Cookie, error) {
+// httpSend makes a single attempt at the request
+func (s *SleepIQ) httpSend(ctx context.Context, method string, url string, payload []byte, headers map[string]string, sendCookies bool) ([]byte, []*http.Cookie, error) {
 	var response []byte
 
-	// Create a new http client
-	client := http.Client{
-		Timeout: 20 * time.Second,
-	}
-
 	// Create the request
-	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
 	if err != nil {
-		return response, cookies, err
+		return response, nil, err
 	}
 
 	// Add headers
-	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	// Add cookies
-	for _, cookie := range cookies {
-		req.AddCookie(cookie)
+	if sendCookies {
+		for _, cookie := range s.auth.sessionCookies() {
+			req.AddCookie(cookie)
+		}
+	}
+
+	// Wait for the rate limit
+	err = s.limiter.wait(ctx, req)
+	if err != nil {
+		return response, nil, err
 	}
 
 	// Make the request
-	res, err := client.Do(req)
+	res, err := s.httpClient.Do(req)
 	if err != nil {
-		return response, cookies, err
+		return response, nil, err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusTooManyRequests {
+		s.limiter.throttled(res)
+	}
+
 	// Read the response
 	response, err = ioutil.ReadAll(res.Body)
 	if err != nil {
-		return response, cookies, err
+		return response, nil, err
 	}
 
-	cookies = res.Cookies()
-
-	return response, cookies, nil
-}
-
-// httpPost conducts a POST request with the provided url. The function returns the response from the
-// service as a byte array.
-func httpPost(url string, payload []byte) ([]byte, error) {
-	var response []byte
-
-	// Create a new http client
-	client := http.Client{
-		Timeout: 20 * time.Second,
+	// Keep the session cookies that were refreshed by the service
+	cookies := res.Cookies()
+	if sendCookies && len(cookies) > 0 {
+		s.auth.updateCookies(cookies)
 	}
 
-	// Create the request
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
-	if err != nil {
-		return response, err
-	}
-
-	// Add headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Ocp-Apim-Subscription-Key", "3c924e14923642baa1c4ad1d5096a1c5")
-	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
+	return response, cookies, checkResponse(res.StatusCode, response)
+}
 
-	// Make the request
-	res, err := client.Do(req)
-	if err != nil {
-		return response, err
-	}
-	defer res.Body.Close()
+// checkResponse returns an *APIError when the service reports a failure, either
+// through the status code or through the Error object in the response body
+func checkResponse(statusCode int, body []byte) error {
+	// Not every response is JSON so a failure to read the body is not an error
+	var serviceResponse controlResponse
+	json.Unmarshal(body, &serviceResponse)
 
-	// Read the response
-	response, err = ioutil.ReadAll(res.Body)
-	if err != nil {
-		return response, err
+	if serviceResponse.Error.Code > 0 || statusCode >= http.StatusBadRequest {
+		return newAPIError(statusCode, serviceResponse.Error)
 	}
 
-	return response, nil
+	return nil
 }
@@ -1,14 +1,31 @@
 package sleepiq
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
 )
 
+// newTestServer starts a fake SleepIQ service and returns a client that is
+// configured to use it
+func newTestServer() (*sleepiqtest.Server, *SleepIQ) {
+	server := sleepiqtest.NewServer()
+	siq := New(WithBaseURL(server.BaseURL()), WithInsightsBaseURL(server.InsightsBaseURL()))
+	return server, siq
+}
+
 func TestHttpGetSuccess(t *testing.T) {
-	var cookies []*http.Cookie
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
 
-	responseBytes, err := httpGet("http://msn.com", cookies, getHeaders())
+	siq := New()
+	responseBytes, err := siq.httpGet(context.Background(), server.URL, getHeaders())
 	if err != nil {
 		t.Error("request failed - expected success", err)
 		return
@@ -20,9 +37,8 @@ func TestHttpGetSuccess(t *testing.T) {
 }
 
 func TestHttpGetBadUrl(t *testing.T) {
-	var cookies []*http.Cookie
-
-	_, err := httpGet("foo://invalid.com", cookies, getHeaders())
+	siq := New()
+	_, err := siq.httpGet(context.Background(), "foo://invalid.com", getHeaders())
 	if err == nil {
 		t.Error("request succeeded - expected failure", err)
 		return
@@ -31,10 +47,59 @@ func TestHttpGetBadUrl(t *testing.T) {
 
 func TestHttpPutBadUrl(t *testing.T) {
 	testBytes := []byte("testing")
-	var cookies []*http.Cookie
-	_, _, err := httpPut("foo://invalid.com", testBytes, cookies)
+	siq := New()
+	_, _, err := siq.httpPut(context.Background(), "foo://invalid.com", testBytes)
 	if err == nil {
 		t.Error("request succeeded - expected failure", err)
 		return
 	}
 }
+
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHttpGetCustomTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	siq := New(WithTransport(transport))
+
+	responseBytes, err := siq.httpGet(context.Background(), server.URL, getHeaders())
+	if err != nil {
+		t.Error("request failed - expected success", err)
+		return
+	}
+
+	if string(responseBytes) != "ok" {
+		t.Errorf("unexpected response. Expected=%s, Actual=%s", "ok", string(responseBytes))
+	}
+
+	if transport.requests != 1 {
+		t.Errorf("custom transport was not used. Expected=%d, Actual=%d", 1, transport.requests)
+	}
+}
+
+func TestHttpGetCanceledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	siq := New()
+	_, err := siq.httpGet(ctx, server.URL, getHeaders())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("request was not canceled. Expected=%s, Actual=%v", context.Canceled, err)
+	}
+}
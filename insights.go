@@ -1,8 +1,8 @@
 package sleepiq
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -47,34 +47,35 @@ type SleeperActivities struct {
 
 // InsightsActiviy obtains activites that are sourced from external
 // monitors such as Apple Watch and Nest thermostats
-func (s SleepIQ) InsightsActiviy(sleeperID string, startDate time.Time, endDate time.Time) (SleeperActivities, error) {
+func (s *SleepIQ) InsightsActiviy(sleeperID string, startDate time.Time, endDate time.Time) (SleeperActivities, error) {
+	return s.InsightsActiviyContext(context.Background(), sleeperID, startDate, endDate)
+}
+
+// InsightsActiviyContext is like InsightsActiviy but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) InsightsActiviyContext(ctx context.Context, sleeperID string, startDate time.Time, endDate time.Time) (SleeperActivities, error) {
 	var response SleeperActivities
 
 	// Bail if there is not an active logged-in session
-	if !s.isInsightsLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.insightsLoggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://sleepiqapi.azure-api.net/prod/activities?sleeperId={{sleeperId}}&startDate={{startDate}}&endDate={{endDate}}&access_token={{token}}", "{{token}}", s.insightsToken, -1)
+	url := strings.Replace(s.insightsBaseURL+"/activities?sleeperId={{sleeperId}}&startDate={{startDate}}&endDate={{endDate}}&access_token={{token}}", "{{token}}", s.auth.token(), -1)
 	url = strings.Replace(url, "{{startDate}}", startDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{endDate}}", endDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{sleeperId}}", sleeperID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getInsightsHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getInsightsHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve Insights activities - %s", err)
+		return response, fmt.Errorf("unable to retrieve Insights activities - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read Insights activities - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read Insights activities - %w", err)
 	}
 
 	return response, nil
@@ -107,31 +108,32 @@ type InsightProvidersStatus struct {
 
 // InsightsProviders retrieves information about the status of the
 // various activity monitors that are supported by SleepIQ
-func (s SleepIQ) InsightsProviders() (InsightProvidersStatus, error) {
+func (s *SleepIQ) InsightsProviders() (InsightProvidersStatus, error) {
+	return s.InsightsProvidersContext(context.Background())
+}
+
+// InsightsProvidersContext is like InsightsProviders but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) InsightsProvidersContext(ctx context.Context) (InsightProvidersStatus, error) {
 	var response InsightProvidersStatus
 
 	// Bail if there is not an active logged-in session
-	if !s.isInsightsLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.insightsLoggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://sleepiqapi.azure-api.net/prod/providers/?access_token={{token}}", "{{token}}", s.insightsToken, -1)
+	url := strings.Replace(s.insightsBaseURL+"/providers/?access_token={{token}}", "{{token}}", s.auth.token(), -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getInsightsHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getInsightsHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve Insights providers - %s", err)
+		return response, fmt.Errorf("unable to retrieve Insights providers - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read Insights providers - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read Insights providers - %w", err)
 	}
 
 	return response, nil
@@ -155,68 +157,70 @@ type RelativeInsights struct {
 
 // InsightsLikeMe retrieves historical data for people with similar sleep
 // patterns to yourself
-func (s SleepIQ) InsightsLikeMe(sleeperID string, startDate time.Time, endDate time.Time) (RelativeInsights, error) {
+func (s *SleepIQ) InsightsLikeMe(sleeperID string, startDate time.Time, endDate time.Time) (RelativeInsights, error) {
+	return s.InsightsLikeMeContext(context.Background(), sleeperID, startDate, endDate)
+}
+
+// InsightsLikeMeContext is like InsightsLikeMe but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) InsightsLikeMeContext(ctx context.Context, sleeperID string, startDate time.Time, endDate time.Time) (RelativeInsights, error) {
 	var response RelativeInsights
 
 	// Bail if there is not an active logged-in session
-	if !s.isInsightsLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.insightsLoggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://sleepiqapi.azure-api.net/prod/insights/historical/likeme/{{sleeperId}}?start={{startDate}}&end={{endDate}}&access_token={{token}}", "{{token}}", s.insightsToken, -1)
+	url := strings.Replace(s.insightsBaseURL+"/insights/historical/likeme/{{sleeperId}}?start={{startDate}}&end={{endDate}}&access_token={{token}}", "{{token}}", s.auth.token(), -1)
 	url = strings.Replace(url, "{{startDate}}", startDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{endDate}}", endDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{sleeperId}}", sleeperID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getInsightsHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getInsightsHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve Insights like me - %s", err)
+		return response, fmt.Errorf("unable to retrieve Insights like me - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read Insights like me - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read Insights like me - %w", err)
 	}
 
 	return response, nil
 }
 
 // InsightsNearMe retrieves historical data for people near my location
-func (s SleepIQ) InsightsNearMe(sleeperID string, startDate time.Time, endDate time.Time) (RelativeInsights, error) {
+func (s *SleepIQ) InsightsNearMe(sleeperID string, startDate time.Time, endDate time.Time) (RelativeInsights, error) {
+	return s.InsightsNearMeContext(context.Background(), sleeperID, startDate, endDate)
+}
+
+// InsightsNearMeContext is like InsightsNearMe but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) InsightsNearMeContext(ctx context.Context, sleeperID string, startDate time.Time, endDate time.Time) (RelativeInsights, error) {
 	var response RelativeInsights
 
 	// Bail if there is not an active logged-in session
-	if !s.isInsightsLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.insightsLoggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://sleepiqapi.azure-api.net/prod/insights/historical/nearme/{{sleeperId}}?start={{startDate}}&end={{endDate}}&access_token={{token}}", "{{token}}", s.insightsToken, -1)
+	url := strings.Replace(s.insightsBaseURL+"/insights/historical/nearme/{{sleeperId}}?start={{startDate}}&end={{endDate}}&access_token={{token}}", "{{token}}", s.auth.token(), -1)
 	url = strings.Replace(url, "{{startDate}}", startDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{endDate}}", endDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{sleeperId}}", sleeperID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getInsightsHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getInsightsHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve Insights like me - %s", err)
+		return response, fmt.Errorf("unable to retrieve Insights like me - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read Insights like me - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read Insights like me - %w", err)
 	}
 
 	return response, nil
@@ -244,34 +248,35 @@ type MyInsights struct {
 }
 
 // InsightsMe retrieves historical insight data about ones self
-func (s SleepIQ) InsightsMe(sleeperID string, startDate time.Time, endDate time.Time) (MyInsights, error) {
+func (s *SleepIQ) InsightsMe(sleeperID string, startDate time.Time, endDate time.Time) (MyInsights, error) {
+	return s.InsightsMeContext(context.Background(), sleeperID, startDate, endDate)
+}
+
+// InsightsMeContext is like InsightsMe but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) InsightsMeContext(ctx context.Context, sleeperID string, startDate time.Time, endDate time.Time) (MyInsights, error) {
 	var response MyInsights
 
 	// Bail if there is not an active logged-in session
-	if !s.isInsightsLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.insightsLoggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://sleepiqapi.azure-api.net/prod/insights/historical/sleeper/{{sleeperId}}?start={{startDate}}&end={{endDate}}&access_token={{token}}", "{{token}}", s.insightsToken, -1)
+	url := strings.Replace(s.insightsBaseURL+"/insights/historical/sleeper/{{sleeperId}}?start={{startDate}}&end={{endDate}}&access_token={{token}}", "{{token}}", s.auth.token(), -1)
 	url = strings.Replace(url, "{{startDate}}", startDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{endDate}}", endDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{sleeperId}}", sleeperID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getInsightsHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getInsightsHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve Insights like me - %s", err)
+		return response, fmt.Errorf("unable to retrieve Insights like me - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read Insights like me - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read Insights like me - %w", err)
 	}
 
 	return response, nil
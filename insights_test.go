@@ -1,15 +1,17 @@
 package sleepiq
 
 import (
-	"os"
 	"testing"
 	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
 )
 
 func TestInsightsSuccess(t *testing.T) {
-	siq := New()
+	server, siq := newTestServer()
+	defer server.Close()
 
-	response, err := siq.InsightsLogin(os.Getenv("sleepiq_username"), os.Getenv("sleepiq_password"))
+	response, err := siq.InsightsLogin(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
 	if err != nil {
 		t.Errorf("login failed - expected success. %s", err)
 		return
@@ -1,17 +1,39 @@
 package sleepiq
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
 
 // Version: 1.0.0
 
+// Default service locations
+const (
+	DefaultBaseURL         = "https://prod-api.sleepiq.sleepnumber.com/rest"
+	DefaultInsightsBaseURL = "https://sleepiqapi.azure-api.net/prod"
+)
+
+// DefaultTimeout is the request timeout of the http client that is used
+// when no client is provided via WithHTTPClient
+const DefaultTimeout = 20 * time.Second
+
 // SleepIQ is the main struct which all methods are associated with
-// as well as contains global settings for use by all methods
+// as well as contains global settings for use by all methods. A SleepIQ
+// instance is safe for concurrent use by multiple goroutines.
 type SleepIQ struct {
-	isLoggedIn         bool
-	isInsightsLoggedIn bool
-	loginKey           string
-	insightsToken      string
-	cookies            []*http.Cookie
+	auth            *authState
+	reloginMu       sync.Mutex
+	credentials     CredentialsProvider
+	baseURL         string
+	insightsBaseURL string
+	httpClient      *http.Client
+	retryPolicy     RetryPolicy
+	limiter         *rateLimiter
+
+	capabilitiesMu sync.Mutex
+	capabilities   map[string]Capabilities
 }
 
 // ServiceError contains error information for calls to the sleepiq service
@@ -20,8 +42,73 @@ type ServiceError struct {
 	Message string `json:"Message"`
 }
 
+// Option configures optional settings of a SleepIQ instance
+type Option func(*SleepIQ)
+
+// WithBaseURL sets the base URL of the SleepIQ REST service. This is useful
+// for pointing the client at a staging or mock server.
+func WithBaseURL(baseURL string) Option {
+	return func(s *SleepIQ) {
+		s.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithInsightsBaseURL sets the base URL of the SleepIQ Insights service
+func WithInsightsBaseURL(baseURL string) Option {
+	return func(s *SleepIQ) {
+		s.insightsBaseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http client that is used for all requests made
+// to the SleepIQ services, including logins. Sharing a client allows
+// connections to be reused and proxies, TLS settings or tracing to be
+// configured by the caller.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *SleepIQ) {
+		if client != nil {
+			s.httpClient = client
+		}
+	}
+}
+
+// WithTransport sets the transport used by the default http client. This
+// is a shorthand for WithHTTPClient when only the RoundTripper needs to
+// be replaced.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *SleepIQ) {
+		s.httpClient = &http.Client{
+			Transport: transport,
+			Timeout:   DefaultTimeout,
+		}
+	}
+}
+
+// WithCredentials sets the provider that supplies the username and password
+// used to login again when the session expires. Without a provider the
+// credentials passed to the most recent login are used.
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(s *SleepIQ) {
+		s.credentials = provider
+	}
+}
+
 // New creates a new instance of SleepIQ
-func New() SleepIQ {
-	s := SleepIQ{}
+func New(options ...Option) *SleepIQ {
+	s := &SleepIQ{
+		auth:            &authState{},
+		baseURL:         DefaultBaseURL,
+		insightsBaseURL: DefaultInsightsBaseURL,
+		httpClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+		retryPolicy: NoRetryPolicy,
+		limiter:     &rateLimiter{},
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
 	return s
 }
@@ -0,0 +1,176 @@
+package sleepiq
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// RATE LIMITING
+// ============================================================================
+
+// RateLimitDelay describes a request that was delayed before being sent,
+// either by the client-side rate limit or because the service asked the
+// client to back off with a Retry-After header
+type RateLimitDelay struct {
+	Method     string
+	Path       string
+	Delay      time.Duration
+	RetryAfter bool // The delay was requested by the service
+}
+
+// WithRateLimit limits the rate of requests sent to the SleepIQ services.
+// Requests are allowed at requestsPerSecond on average with bursts of up to
+// burst requests. The limit is shared by all endpoints.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(s *SleepIQ) {
+		s.limiter.setLimit(requestsPerSecond, burst)
+	}
+}
+
+// WithRateLimitObserver sets a function that is called whenever a request
+// is delayed, for example to log throttling
+func WithRateLimitObserver(observer func(RateLimitDelay)) Option {
+	return func(s *SleepIQ) {
+		s.limiter.observer = observer
+	}
+}
+
+// rateLimiter is a token bucket that delays requests exceeding the configured
+// rate. It also pauses all requests when the service returns 429 Too Many
+// Requests with a Retry-After header. A rate of zero disables the bucket.
+type rateLimiter struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	observer     func(RateLimitDelay)
+}
+
+// setLimit configures the rate and burst of the token bucket
+func (l *rateLimiter) setLimit(requestsPerSecond float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.rate = requestsPerSecond
+	l.burst = float64(burst)
+	l.tokens = float64(burst)
+	l.last = time.Time{}
+}
+
+// wait blocks until the request may be sent or the context is done
+func (l *rateLimiter) wait(ctx context.Context, req *http.Request) error {
+	delay, retryAfter, reserved := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	if l.observer != nil {
+		l.observer(RateLimitDelay{
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			Delay:      delay,
+			RetryAfter: retryAfter,
+		})
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		if reserved {
+			l.release()
+		}
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the request
+// must wait before it may be sent
+func (l *rateLimiter) reserve(now time.Time) (delay time.Duration, retryAfter bool, reserved bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.blockedUntil) {
+		delay = l.blockedUntil.Sub(now)
+		retryAfter = true
+	}
+
+	if l.rate <= 0 {
+		return delay, retryAfter, false
+	}
+
+	// Refill the bucket for the time that has passed since the last request
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	// Take a token. A negative balance is the wait time for the request.
+	l.tokens--
+	if l.tokens < 0 {
+		tokenDelay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+		if tokenDelay > delay {
+			delay = tokenDelay
+			retryAfter = false
+		}
+	}
+
+	return delay, retryAfter, true
+}
+
+// release returns a token of a request that was not sent
+func (l *rateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// throttled pauses all requests for the duration requested by the service
+func (l *rateLimiter) throttled(res *http.Response) {
+	retryAfter := parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+	if retryAfter <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(retryAfter)
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of
+// seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0
+	}
+
+	return date.Sub(now)
+}
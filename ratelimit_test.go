@@ -0,0 +1,102 @@
+package sleepiq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitDelaysBurst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var delays []RateLimitDelay
+	siq := New(WithRateLimit(20, 2), WithRateLimitObserver(func(delay RateLimitDelay) {
+		delays = append(delays, delay)
+	}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := siq.httpGet(context.Background(), server.URL+"/bed", getHeaders())
+		if err != nil {
+			t.Error("request failed - expected success", err)
+			return
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("requests were not rate limited. Elapsed=%s", elapsed)
+	}
+
+	if len(delays) != 1 {
+		t.Errorf("unexpected number of delayed requests. Expected=%d, Actual=%d", 1, len(delays))
+		return
+	}
+
+	if delays[0].Path != "/bed" || delays[0].RetryAfter {
+		t.Errorf("unexpected delay details. Path=%s, RetryAfter=%t", delays[0].Path, delays[0].RetryAfter)
+	}
+}
+
+func TestRateLimitHonorsRetryAfter(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var delays []RateLimitDelay
+	siq := New(WithRateLimitObserver(func(delay RateLimitDelay) {
+		delays = append(delays, delay)
+	}))
+
+	_, err := siq.httpGet(context.Background(), server.URL, getHeaders())
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited. Actual=%v", err)
+	}
+
+	// The next request must wait for the service to accept requests again
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = siq.httpGet(ctx, server.URL, getHeaders())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("request was not delayed. Actual=%v", err)
+	}
+
+	if len(delays) != 1 || !delays[0].RetryAfter {
+		t.Errorf("delay requested by the service was not observed. Delays=%v", delays)
+	}
+
+	if attempts != 1 {
+		t.Errorf("request was sent during the Retry-After period. Attempts=%d", attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if actual := parseRetryAfter("5", now); actual != 5*time.Second {
+		t.Errorf("unexpected Retry-After seconds. Expected=%s, Actual=%s", 5*time.Second, actual)
+	}
+
+	date := now.Add(time.Minute).Format(http.TimeFormat)
+	if actual := parseRetryAfter(date, now); actual != time.Minute {
+		t.Errorf("unexpected Retry-After date. Expected=%s, Actual=%s", time.Minute, actual)
+	}
+
+	if actual := parseRetryAfter("soon", now); actual != 0 {
+		t.Errorf("invalid Retry-After should be ignored. Actual=%s", actual)
+	}
+}
@@ -0,0 +1,179 @@
+package sleepiq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ============================================================================
+// RETRY POLICY
+// ============================================================================
+
+// RetryPolicy describes how requests that fail with a transient error are
+// retried. Requests using one of the RetryMethods are retried after transient
+// network errors and after responses with one of the RetryStatusCodes. Requests using
+// any other method, such as the PUT requests that control the bed, are only
+// retried when the service cannot have acted on them: when no connection
+// could be established or when the service rejected the request with
+// 429 Too Many Requests.
+type RetryPolicy struct {
+	MaxAttempts      int           // Total number of attempts, including the first one
+	InitialBackoff   time.Duration // Wait time before the first retry
+	MaxBackoff       time.Duration // Upper limit of the wait time between attempts
+	Multiplier       float64       // Factor applied to the wait time after each attempt
+	Jitter           float64       // Fraction of the wait time that is randomized, between 0 and 1
+	RetryStatusCodes []int
+	RetryMethods     []string
+}
+
+// NoRetryPolicy makes a single attempt for each request. It is used unless
+// another policy is configured with WithRetryPolicy.
+var NoRetryPolicy = RetryPolicy{
+	MaxAttempts: 1,
+}
+
+// DefaultRetryPolicy retries reads up to three times with an exponential
+// backoff starting at half a second
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+	RetryStatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	RetryMethods: []string{
+		http.MethodGet,
+	},
+}
+
+// WithRetryPolicy sets the policy used to retry requests that fail with a
+// transient error
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *SleepIQ) {
+		s.retryPolicy = policy
+	}
+}
+
+// retryable reports whether a request with the given method that failed with
+// the given error may be sent again
+func (p RetryPolicy) retryable(method string, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	safeMethod := containsString(p.RetryMethods, method)
+
+	// Errors reported by the service
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if !containsInt(p.RetryStatusCodes, apiErr.StatusCode) {
+			return false
+		}
+		return safeMethod || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	// Network errors. Failures such as an unsupported scheme or an invalid
+	// certificate would fail again and are never retried.
+	if !transientNetworkError(err) {
+		return false
+	}
+	if safeMethod {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// backoff returns the time to wait after the given attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	// Spread the wait time by up to the jitter fraction in either direction
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// httpRetry sends the request and retries transient failures according to the
+// retry policy
+func (s *SleepIQ) httpRetry(ctx context.Context, method string, url string, payload []byte, headers map[string]string, sendCookies bool) ([]byte, []*http.Cookie, error) {
+	policy := s.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		response, cookies, err := s.httpSend(ctx, method, url, payload, headers, sendCookies)
+		if err == nil || attempt >= policy.MaxAttempts || !policy.retryable(method, err) {
+			return response, cookies, err
+		}
+
+		// Wait before the next attempt
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, cookies, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// ============================================================================
+// SUPPORTING FUNCTIONS
+// ============================================================================
+
+// transientNetworkError reports whether the request failed because of a
+// timeout, a refused or reset connection or a response that was cut short
+func transientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// containsString reports whether the value is in the list
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt reports whether the value is in the list
+func containsInt(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
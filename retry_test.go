@@ -0,0 +1,172 @@
+package sleepiq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy retries quickly so that tests do not have to wait
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:      3,
+	InitialBackoff:   time.Millisecond,
+	MaxBackoff:       5 * time.Millisecond,
+	Multiplier:       2,
+	RetryStatusCodes: DefaultRetryPolicy.RetryStatusCodes,
+	RetryMethods:     DefaultRetryPolicy.RetryMethods,
+}
+
+func TestRetryGetUntilSuccess(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	siq := New(WithRetryPolicy(testRetryPolicy))
+	_, err := siq.httpGet(context.Background(), server.URL, getHeaders())
+	if err != nil {
+		t.Error("request failed - expected success", err)
+		return
+	}
+
+	if attempts != 3 {
+		t.Errorf("unexpected number of attempts. Expected=%d, Actual=%d", 3, attempts)
+	}
+}
+
+func TestRetryPutNotRetriedAfterServerError(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	siq := New(WithRetryPolicy(testRetryPolicy))
+	_, _, err := siq.httpPut(context.Background(), server.URL, []byte("{}"))
+	if err == nil {
+		t.Error("request succeeded - expected failure")
+		return
+	}
+
+	if attempts != 1 {
+		t.Errorf("control request should not have been retried. Expected=%d, Actual=%d", 1, attempts)
+	}
+}
+
+func TestRetryPutRetriedWhenThrottled(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	siq := New(WithRetryPolicy(testRetryPolicy))
+	_, _, err := siq.httpPut(context.Background(), server.URL, []byte("{}"))
+	if err != nil {
+		t.Error("request failed - expected success", err)
+		return
+	}
+
+	if attempts != 2 {
+		t.Errorf("unexpected number of attempts. Expected=%d, Actual=%d", 2, attempts)
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	siq := New()
+	siq.httpGet(context.Background(), server.URL, getHeaders())
+
+	if attempts != 1 {
+		t.Errorf("unexpected number of attempts. Expected=%d, Actual=%d", 1, attempts)
+	}
+}
+
+// roundTripFunc is a transport that answers every request with the function
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	tests := []struct {
+		err      error
+		attempts int
+	}{
+		{errors.New("x509: certificate signed by unknown authority"), 1},
+		{io.ErrUnexpectedEOF, 3},
+	}
+
+	for _, test := range tests {
+		attempts := 0
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return nil, test.err
+		})
+
+		siq := New(WithRetryPolicy(testRetryPolicy), WithTransport(transport))
+		_, err := siq.httpGet(context.Background(), "https://api.sleepiq.sleepnumber.com/rest/bed", getHeaders())
+		if err == nil {
+			t.Errorf("request succeeded - expected failure")
+		}
+
+		if attempts != test.attempts {
+			t.Errorf("unexpected number of attempts after '%s'. Expected=%d, Actual=%d", test.err, test.attempts, attempts)
+		}
+	}
+
+	// Unsupported schemes fail before any request is made and are not retried
+	start := time.Now()
+	siq := New(WithRetryPolicy(DefaultRetryPolicy))
+	_, err := siq.httpGet(context.Background(), "foo://x", nil)
+	if err == nil || time.Since(start) > DefaultRetryPolicy.InitialBackoff {
+		t.Errorf("unsupported scheme should fail without a retry. Elapsed=%s, Error=%v", time.Since(start), err)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, backoff := range expected {
+		if actual := policy.backoff(i + 1); actual != backoff {
+			t.Errorf("unexpected backoff for attempt %d. Expected=%s, Actual=%s", i+1, backoff, actual)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		actual := policy.backoff(1)
+		if actual < 500*time.Millisecond || actual > 1500*time.Millisecond {
+			t.Errorf("backoff with jitter is out of range. Actual=%s", actual)
+			return
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package sleepiq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ============================================================================
+// SESSION
+// ============================================================================
+
+// Session describes a logged-in session with the SleepIQ and Insights
+// services. A session can be saved and later restored with NewFromSession so
+// that a new process does not need to login again. The password is never
+// part of a session.
+type Session struct {
+	LoginKey      string         `json:"loginKey,omitempty"`
+	Cookies       []*http.Cookie `json:"cookies,omitempty"`
+	InsightsToken string         `json:"insightsToken,omitempty"`
+	LoginTime     time.Time      `json:"loginTime"`
+}
+
+// Session returns the current session of the SleepIQ instance
+func (s *SleepIQ) Session() (Session, error) {
+	session, ok := s.auth.session()
+
+	// Bail if there is not an active logged-in session
+	if !ok {
+		return session, ErrNotLoggedIn
+	}
+
+	return session, nil
+}
+
+// NewFromSession creates a new instance of SleepIQ that continues the provided
+// session. Since the session does not contain a password, WithCredentials must
+// be used if the session should be renewed once it expires.
+func NewFromSession(session Session, options ...Option) (*SleepIQ, error) {
+	s := New(options...)
+
+	err := session.Validate()
+	if err != nil {
+		return s, err
+	}
+
+	s.auth.restore(session)
+
+	return s, nil
+}
+
+// Validate checks whether the session can be used to make requests. It does
+// not contact the service so the session may still have been invalidated.
+func (session Session) Validate() error {
+	if session.LoginKey == "" && session.InsightsToken == "" {
+		return fmt.Errorf("%w - no login key or insights token", ErrInvalidSession)
+	}
+
+	now := time.Now()
+	for _, cookie := range session.Cookies {
+		if !cookie.Expires.IsZero() && cookie.Expires.Before(now) {
+			return fmt.Errorf("%w - cookie '%s' expired at %s", ErrInvalidSession, cookie.Name, cookie.Expires.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// Save writes the session to the writer as JSON. The session grants access to
+// the account, so it should be stored as carefully as a password.
+func (session Session) Save(w io.Writer) error {
+	err := json.NewEncoder(w).Encode(session)
+	if err != nil {
+		return fmt.Errorf("could not save session - %w", err)
+	}
+
+	return nil
+}
+
+// LoadSession reads a session that was written with Save and validates it
+func LoadSession(r io.Reader) (Session, error) {
+	var session Session
+
+	err := json.NewDecoder(r).Decode(&session)
+	if err != nil {
+		return session, fmt.Errorf("could not read session - %w", err)
+	}
+
+	return session, session.Validate()
+}
+
+// session returns a copy of the session state and whether there is an active
+// session with either service
+func (a *authState) session() (Session, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.isLoggedIn && !a.isInsightsLoggedIn {
+		return Session{}, false
+	}
+
+	return Session{
+		LoginKey:      a.loginKey,
+		Cookies:       append([]*http.Cookie(nil), a.cookies...),
+		InsightsToken: a.insightsToken,
+		LoginTime:     a.loginTime,
+	}, true
+}
+
+// restore replaces the session state with the provided session
+func (a *authState) restore(session Session) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.loginKey = session.LoginKey
+	a.isLoggedIn = session.LoginKey != ""
+	a.cookies = session.Cookies
+	a.insightsToken = session.InsightsToken
+	a.isInsightsLoggedIn = session.InsightsToken != ""
+	a.loginTime = session.LoginTime
+}
@@ -0,0 +1,136 @@
+package sleepiq
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
+)
+
+func TestSessionSaveAndRestore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "abc"})
+			w.Write([]byte(`{"userId":"1","key":"testKey"}`))
+		case "/bed":
+			cookie, err := r.Cookie("JSESSIONID")
+			if r.URL.Query().Get("_k") != "testKey" || err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"beds":[{"bedId":"1"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	siq := New(WithBaseURL(server.URL))
+	_, err := siq.Login("JohnDoe@live.com", "password")
+	if err != nil {
+		t.Error("login failed - expected success", err)
+		return
+	}
+
+	session, err := siq.Session()
+	if err != nil {
+		t.Errorf("could not get session - %s", err)
+		return
+	}
+
+	var buffer bytes.Buffer
+	err = session.Save(&buffer)
+	if err != nil {
+		t.Errorf("could not save session - %s", err)
+		return
+	}
+
+	restored, err := LoadSession(&buffer)
+	if err != nil {
+		t.Errorf("could not load session - %s", err)
+		return
+	}
+
+	restoredSiq, err := NewFromSession(restored, WithBaseURL(server.URL))
+	if err != nil {
+		t.Errorf("could not restore session - %s", err)
+		return
+	}
+
+	beds, err := restoredSiq.Beds()
+	if err != nil {
+		t.Errorf("could not get beds with restored session - %s", err)
+		return
+	}
+
+	if len(beds.Beds) != 1 {
+		t.Errorf("unexpected number of beds. Expected=%d, Actual=%d", 1, len(beds.Beds))
+	}
+}
+
+func TestSessionNotLoggedIn(t *testing.T) {
+	siq := New()
+	_, err := siq.Session()
+
+	if !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("expected ErrNotLoggedIn. Actual=%v", err)
+	}
+}
+
+func TestSessionValidate(t *testing.T) {
+	err := Session{}.Validate()
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("empty session should be invalid. Actual=%v", err)
+	}
+
+	expired := Session{
+		LoginKey: "testKey",
+		Cookies:  []*http.Cookie{{Name: "JSESSIONID", Value: "abc", Expires: time.Now().Add(-time.Hour)}},
+	}
+	_, err = NewFromSession(expired)
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("session with an expired cookie should be invalid. Actual=%v", err)
+	}
+}
+
+func TestSessionConcurrentLogin(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 5; i++ {
+			siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+		}
+	}()
+
+	for i := 0; i < 5; i++ {
+		session, err := siq.Session()
+		if err != nil {
+			t.Errorf("could not get session - %s", err)
+			continue
+		}
+
+		// The session holds a copy of the cookies
+		session.Cookies = append(session.Cookies[:0], &http.Cookie{Name: "changed"})
+	}
+	wg.Wait()
+
+	session, _ := siq.Session()
+	for _, cookie := range session.Cookies {
+		if cookie.Name == "changed" {
+			t.Error("changing the cookies of a session changed the cookies of the client")
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package sleepiq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// SIDES
+// ============================================================================
+
+// Side is a side of the bed. It is encoded in JSON as a number, the way the
+// service reports the side of a sleeper.
+type Side int
+
+// Sides of the bed
+const (
+	Left  Side = 0
+	Right Side = 1
+)
+
+// Sides of the bed.
+//
+// Deprecated: Use Left and Right.
+const (
+	BedSideLeft  = Left
+	BedSideRight = Right
+)
+
+// Sides lists both sides of the bed, left first
+var Sides = []Side{Left, Right}
+
+// ParseSide parses the name of a side. It accepts "left", "right", "l" and
+// "r" in any case as well as "0" and "1".
+func ParseSide(value string) (Side, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "left", "l", "0":
+		return Left, nil
+	case "right", "r", "1":
+		return Right, nil
+	}
+	return Left, ErrInvalidSide
+}
+
+// String returns "left" or "right"
+func (s Side) String() string {
+	switch s {
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	}
+	return fmt.Sprintf("Side(%d)", int(s))
+}
+
+// valid reports whether the side is Left or Right
+func (s Side) valid() bool {
+	return s == Left || s == Right
+}
+
+// UnmarshalJSON reads a side from a number or from a name accepted by
+// ParseSide
+func (s *Side) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		side, err := ParseSide(name)
+		if err != nil {
+			return err
+		}
+		*s = side
+		return nil
+	}
+
+	value, err := strconv.Atoi(string(data))
+	if err != nil || !Side(value).valid() {
+		return fmt.Errorf("invalid side %s", data)
+	}
+	*s = Side(value)
+	return nil
+}
+
+// sideCode is the form of a side that is sent to the control endpoints,
+// "L" or "R"
+type sideCode Side
+
+// MarshalJSON writes the side as "L" or "R"
+func (s sideCode) MarshalJSON() ([]byte, error) {
+	if Side(s) == Left {
+		return []byte(`"L"`), nil
+	}
+	return []byte(`"R"`), nil
+}
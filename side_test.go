@@ -0,0 +1,98 @@
+package sleepiq
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseSide(t *testing.T) {
+	tests := map[string]Side{
+		"left":  Left,
+		"Left":  Left,
+		"L":     Left,
+		"0":     Left,
+		"right": Right,
+		" R ":   Right,
+		"1":     Right,
+	}
+
+	for value, expected := range tests {
+		side, err := ParseSide(value)
+		if err != nil || side != expected {
+			t.Errorf("failed to parse side %q. Expected=%s, Actual=%s (%v)", value, expected, side, err)
+		}
+	}
+
+	_, err := ParseSide("middle")
+	if !errors.Is(err, ErrInvalidSide) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrInvalidSide, err)
+	}
+
+	if Right.String() != "right" || Side(2).String() != "Side(2)" {
+		t.Errorf("unexpected side names. Actual=%s/%s", Right, Side(2))
+	}
+}
+
+func TestSideJSON(t *testing.T) {
+	var sleeper Sleeper
+	err := json.Unmarshal([]byte(`{"sleeperId":"1","side":1}`), &sleeper)
+	if err != nil || sleeper.Side != Right {
+		t.Errorf("failed to read numeric side. Expected=%s, Actual=%s (%v)", Right, sleeper.Side, err)
+	}
+
+	var side Side
+	err = json.Unmarshal([]byte(`"L"`), &side)
+	if err != nil || side != Left {
+		t.Errorf("failed to read side name. Expected=%s, Actual=%s (%v)", Left, side, err)
+	}
+
+	err = json.Unmarshal([]byte(`2`), &side)
+	if err == nil {
+		t.Error("reading an invalid side succeeded - expected failure")
+	}
+
+	data, err := json.Marshal(sleepNumberSettings{Side: sideCode(Right), SleepNumber: 40})
+	if err != nil || string(data) != `{"side":"R","sleepNumber":40}` {
+		t.Errorf("unexpected control payload. Expected=%s, Actual=%s (%v)", `{"side":"R","sleepNumber":40}`, data, err)
+	}
+}
+
+func TestBedStatusJSON(t *testing.T) {
+	data := []byte(`{"beds":[{"status":1,"bedId":"bed","leftSide":{"isInBed":true,"sleepNumber":40},"rightSide":{"sleepNumber":55}}]}`)
+
+	var status FamilyStatusDetails
+	err := json.Unmarshal(data, &status)
+	if err != nil {
+		t.Fatalf("could not read family status - %s", err)
+	}
+
+	bed, ok := status.Bed("bed")
+	if !ok {
+		t.Fatal("bed is missing from the family status")
+	}
+
+	if !bed.Side(Left).IsInBed || bed.Side(Left).SleepNumber != 40 || bed.Side(Right).SleepNumber != 55 {
+		t.Errorf("unexpected side status. Actual=%+v", bed.Sides)
+	}
+
+	if bed.Side(Side(2)) != (SideStatus{}) {
+		t.Error("an invalid side should have an empty status")
+	}
+
+	if _, ok := status.Bed("unknown"); ok {
+		t.Error("found an unknown bed in the family status")
+	}
+
+	// The status is written back in the form used by the service
+	encoded, err := json.Marshal(bed)
+	if err != nil {
+		t.Fatalf("could not write bed status - %s", err)
+	}
+
+	var decoded BedStatus
+	err = json.Unmarshal(encoded, &decoded)
+	if err != nil || decoded != bed {
+		t.Errorf("bed status did not survive a round trip. Expected=%+v, Actual=%+v (%v)", bed, decoded, err)
+	}
+}
@@ -1,8 +1,8 @@
 package sleepiq
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -42,35 +42,36 @@ type Sleeper struct {
 	Email          string      `json:"email"`
 	Avatar         string      `json:"avatar"`
 	LastLogin      string      `json:"lastLogin"`
-	Side           int         `json:"side"`
+	Side           Side        `json:"side"`
 }
 
 // Sleepers retrieves detailed information about all sleepers (people)
-func (s SleepIQ) Sleepers() (SleeperDetails, error) {
+func (s *SleepIQ) Sleepers() (SleeperDetails, error) {
+	return s.SleepersContext(context.Background())
+}
+
+// SleepersContext is like Sleepers but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) SleepersContext(ctx context.Context) (SleeperDetails, error) {
 	var response SleeperDetails
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/sleeper?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/sleeper?_k={{key}}", "{{key}}", s.auth.key(), -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve sleeper details - %s", err)
+		return response, fmt.Errorf("unable to retrieve sleeper details - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read sleeper details - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read sleeper details - %w", err)
 	}
 
 	return response, nil
@@ -131,33 +132,34 @@ type SleeperActivity struct {
 // as well as the length of time. The 'timeLength' supports the
 // following options: 'd1' (one day), 'w1' (one week) or 'm1'
 // (one year).
-func (s SleepIQ) SleepActivity(date time.Time, timeLength string) (SleeperActivityDetails, error) {
+func (s *SleepIQ) SleepActivity(date time.Time, timeLength string) (SleeperActivityDetails, error) {
+	return s.SleepActivityContext(context.Background(), date, timeLength)
+}
+
+// SleepActivityContext is like SleepActivity but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) SleepActivityContext(ctx context.Context, date time.Time, timeLength string) (SleeperActivityDetails, error) {
 	var response SleeperActivityDetails
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/sleepData/?_k={{key}}&date={{date}}&interval={{interval}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/sleepData/?_k={{key}}&date={{date}}&interval={{interval}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{date}}", date.Format("2006-01-01"), -1)
 	url = strings.Replace(url, "{{interval}}", convertTimeLength(timeLength), -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve sleeper activity - %s", err)
+		return response, fmt.Errorf("unable to retrieve sleeper activity - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read sleeper activity - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read sleeper activity - %w", err)
 	}
 
 	return response, nil
@@ -177,32 +179,33 @@ type SleeperPreferences struct {
 }
 
 // SleeperPreference retrieves preference information for a given sleeper
-func (s SleepIQ) SleeperPreference(sleeperID string) (SleeperPreferences, error) {
+func (s *SleepIQ) SleeperPreference(sleeperID string) (SleeperPreferences, error) {
+	return s.SleeperPreferenceContext(context.Background(), sleeperID)
+}
+
+// SleeperPreferenceContext is like SleeperPreference but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) SleeperPreferenceContext(ctx context.Context, sleeperID string) (SleeperPreferences, error) {
 	var response SleeperPreferences
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/sleeper/{{sleeperId}}/preferences?_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/sleeper/{{sleeperId}}/preferences?_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{sleeperId}}", sleeperID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve sleeper preferences - %s", err)
+		return response, fmt.Errorf("unable to retrieve sleeper preferences - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read sleeper preferences - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read sleeper preferences - %w", err)
 	}
 
 	return response, nil
@@ -260,32 +263,33 @@ type SleeperMonthlySummaryDetails struct {
 }
 
 // SleeperMonthlySummary contains a monthly summary by day for each sleeper.
-func (s SleepIQ) SleeperMonthlySummary(date time.Time) (SleeperMonthlySummaryDetails, error) {
+func (s *SleepIQ) SleeperMonthlySummary(date time.Time) (SleeperMonthlySummaryDetails, error) {
+	return s.SleeperMonthlySummaryContext(context.Background(), date)
+}
+
+// SleeperMonthlySummaryContext is like SleeperMonthlySummary but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) SleeperMonthlySummaryContext(ctx context.Context, date time.Time) (SleeperMonthlySummaryDetails, error) {
 	var response SleeperMonthlySummaryDetails
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/sleepData/byMonth?startDate={{date}}&_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/sleepData/byMonth?startDate={{date}}&_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{date}}", date.Format("2006-01-02"), -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve sleeper monthly summary - %s", err)
+		return response, fmt.Errorf("unable to retrieve sleeper monthly summary - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read sleeper monthly summary - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read sleeper monthly summary - %w", err)
 	}
 
 	return response, nil
@@ -312,34 +316,35 @@ type EditedSleepSessions struct {
 
 // SleeperEditedSessions retrieves information about manually edited
 // sleeps sessions for a given sleeper by the provided date range.
-func (s SleepIQ) SleeperEditedSessions(sleeperID string, startDate time.Time, endDate time.Time) (EditedSleepSessions, error) {
+func (s *SleepIQ) SleeperEditedSessions(sleeperID string, startDate time.Time, endDate time.Time) (EditedSleepSessions, error) {
+	return s.SleeperEditedSessionsContext(context.Background(), sleeperID, startDate, endDate)
+}
+
+// SleeperEditedSessionsContext is like SleeperEditedSessions but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) SleeperEditedSessionsContext(ctx context.Context, sleeperID string, startDate time.Time, endDate time.Time) (EditedSleepSessions, error) {
 	var response EditedSleepSessions
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/sleepData/editedHidden?startDate={{startDate}}&endDate={{endDate}}&sleeperId={{sleeperId}}&_k={{key}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/sleepData/editedHidden?startDate={{startDate}}&endDate={{endDate}}&sleeperId={{sleeperId}}&_k={{key}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{startDate}}", startDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{endDate}}", endDate.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{sleeperId}}", sleeperID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve sleeper edited sessions - %s", err)
+		return response, fmt.Errorf("unable to retrieve sleeper edited sessions - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read sleeper edited sessions - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read sleeper edited sessions - %w", err)
 	}
 
 	return response, nil
@@ -367,33 +372,34 @@ type SleeperNighlyTimeSeriesActivity struct {
 // SleeperNightlyDetailedActivity retrieves detailed nightly sleep activity
 // for a given sleeper for a specific date. 600 'slices' of time are
 // provided per day which equates to 2.4 minutes.
-func (s SleepIQ) SleeperNightlyDetailedActivity(sleeperID string, date time.Time) (SleeperNighlyTimeSeriesActivity, error) {
+func (s *SleepIQ) SleeperNightlyDetailedActivity(sleeperID string, date time.Time) (SleeperNighlyTimeSeriesActivity, error) {
+	return s.SleeperNightlyDetailedActivityContext(context.Background(), sleeperID, date)
+}
+
+// SleeperNightlyDetailedActivityContext is like SleeperNightlyDetailedActivity but uses ctx for cancellation and
+// deadlines of the underlying requests
+func (s *SleepIQ) SleeperNightlyDetailedActivityContext(ctx context.Context, sleeperID string, date time.Time) (SleeperNighlyTimeSeriesActivity, error) {
 	var response SleeperNighlyTimeSeriesActivity
 
 	// Bail if there is not an active logged-in session
-	if !s.isLoggedIn {
-		return response, errors.New("user is not logged-in. Please login and try again")
+	if !s.auth.loggedIn() {
+		return response, ErrNotLoggedIn
 	}
 
 	// Make request
-	url := strings.Replace("https://prod-api.sleepiq.sleepnumber.com/rest/sleepSliceData?_k={{key}}&date={{date}}&sleeper={{sleeperId}}", "{{key}}", s.loginKey, -1)
+	url := strings.Replace(s.baseURL+"/sleepSliceData?_k={{key}}&date={{date}}&sleeper={{sleeperId}}", "{{key}}", s.auth.key(), -1)
 	url = strings.Replace(url, "{{date}}", date.Format("2006-01-02"), -1)
 	url = strings.Replace(url, "{{sleeperId}}", sleeperID, -1)
 
-	responseBytes, err := httpGet(url, s.cookies, getHeaders())
+	responseBytes, err := s.httpGet(ctx, url, getHeaders())
 	if err != nil {
-		return response, fmt.Errorf("unable to retrieve sleeper nightly detailed activity - %s", err)
+		return response, fmt.Errorf("unable to retrieve sleeper nightly detailed activity - %w", err)
 	}
 
 	// Marshal the response to a loginResponse object
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
-		return response, fmt.Errorf("could not read sleeper nightly detailed activity - %s", err)
-	}
-
-	// Check for an error returned from the service
-	if response.Error.Code > 0 {
-		return response, fmt.Errorf("error #%d: %s", response.Error.Code, response.Error.Message)
+		return response, fmt.Errorf("could not read sleeper nightly detailed activity - %w", err)
 	}
 
 	return response, nil
@@ -1,15 +1,17 @@
 package sleepiq
 
 import (
-	"os"
 	"testing"
 	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
 )
 
 func TestSleepersSuccess(t *testing.T) {
-	siq := New()
+	server, siq := newTestServer()
+	defer server.Close()
 
-	response, err := siq.Login(os.Getenv("sleepiq_username"), os.Getenv("sleepiq_password"))
+	response, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
 	if err != nil {
 		t.Error("login failed - expected success", err)
 		return
@@ -184,7 +186,7 @@ func TestConvertMonthlyDateAliasLast(t *testing.T) {
 
 func TestConvertMonthlyDateAliasCurrentMonth(t *testing.T) {
 	now := time.Now()
-	expectedDate := now.Format("2006") + "-01"
+	expectedDate := now.Format("2006-01")
 	actualDate := convertMonthlyDateAlias(now.Format("January"))
 
 	if actualDate != expectedDate {
@@ -0,0 +1,747 @@
+package sleepiqtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IDs of the bed and sleepers of a new server
+const (
+	DefaultBedID          = "-9223372019930000001"
+	DefaultLeftSleeperID  = "-9223372019930000011"
+	DefaultRightSleeperID = "-9223372019930000012"
+)
+
+// Bed is the state of a fake bed
+type Bed struct {
+	ID             string
+	Name           string
+	Model          string
+	Generation     string
+	Size           string
+	Sku            string
+	Base           string
+	DualSleep      bool
+	SleeperLeftID  string
+	SleeperRightID string
+
+	BedType        int
+	BoardFeatures  int
+	FoundationType string
+	IsMoving       bool
+	PumpActiveTask int
+	PauseMode      bool
+
+	UnderbedLightAuto   bool
+	InBedTimeout        int
+	OutOfBedTimeout     int
+	AdjustmentThreshold int
+	PollFrequency       int
+
+	Left  BedSide
+	Right BedSide
+
+	// Outlets holds the accessory outlets 1 to 4, where outlets 3 and 4 are the
+	// right and left underbed lights
+	Outlets [4]Outlet
+
+	// Times at which the pump starts and finishes a sleep number change
+	pumpStart time.Time
+	pumpDone  time.Time
+}
+
+// BedSide is the state of one side of a fake bed
+type BedSide struct {
+	InBed               bool
+	Pressure            int
+	SleepNumber         int
+	FavoriteSleepNumber int
+	AlertID             int
+	AlertMessage        string
+	Preset              int
+	TimedPreset         int
+	PresetTimer         int // Seconds before the side returns to flat
+	HeadPosition        int
+	FootPosition        int
+	FootWarmingTemp     int
+	FootWarmingTimer    int
+	UnderbedLightPWM    int
+	ResponsiveAir       bool
+
+	// Sleep number the pump is adjusting the side to, zero when no change is pending
+	targetSleepNumber int
+}
+
+// Outlet is the state of an accessory outlet of a fake bed
+type Outlet struct {
+	Name  string
+	On    bool
+	Timer int
+}
+
+// Foundation presets and the head and foot positions they move to
+const (
+	presetNone     = 0
+	presetFavorite = 1
+	presetRead     = 2
+	presetWatchTV  = 3
+	presetFlat     = 4
+	presetZeroG    = 5
+	presetSnore    = 6
+)
+
+var presetNames = map[int]string{
+	presetNone:     "Not at preset",
+	presetFavorite: "Favorite",
+	presetRead:     "Read",
+	presetWatchTV:  "Watch TV",
+	presetFlat:     "Flat",
+	presetZeroG:    "Zero G",
+	presetSnore:    "Snore",
+}
+
+var presetPositions = map[int][2]int{
+	presetFavorite: {30, 10},
+	presetRead:     {45, 10},
+	presetWatchTV:  {60, 20},
+	presetFlat:     {0, 0},
+	presetZeroG:    {15, 30},
+	presetSnore:    {10, 0},
+}
+
+// newBed creates a king size bed with a FlexFit base, foot warmers and
+// underbed lights
+func newBed(bedID string, sleeperLeftID string, sleeperRightID string) *Bed {
+	side := BedSide{
+		Pressure:            1000,
+		SleepNumber:         50,
+		FavoriteSleepNumber: 50,
+		AlertMessage:        "No Alert",
+		Preset:              presetFlat,
+		UnderbedLightPWM:    30,
+		ResponsiveAir:       true,
+	}
+
+	return &Bed{
+		ID:                  bedID,
+		Name:                "Bedroom",
+		Model:               "i8",
+		Generation:          "360",
+		Size:                "KING",
+		Sku:                 "QI8",
+		Base:                "FlexFit 3",
+		DualSleep:           true,
+		SleeperLeftID:       sleeperLeftID,
+		SleeperRightID:      sleeperRightID,
+		BedType:             2,
+		BoardFeatures:       0x1e,
+		FoundationType:      "splitKing",
+		InBedTimeout:        20,
+		OutOfBedTimeout:     60,
+		AdjustmentThreshold: 5,
+		PollFrequency:       1,
+		Left:                side,
+		Right:               side,
+		Outlets: [4]Outlet{
+			{Name: "Right Nightstand"},
+			{Name: "Left Nightstand"},
+			{Name: "Right Underbed Light"},
+			{Name: "Left Underbed Light"},
+		},
+	}
+}
+
+// side returns the side of the bed named by a side parameter of the SleepIQ
+// service such as "L", "Right" or "left"
+func (b *Bed) side(name string) *BedSide {
+	switch strings.ToUpper(name) {
+	case "L", "LEFT":
+		return &b.Left
+	case "R", "RIGHT":
+		return &b.Right
+	}
+	return nil
+}
+
+// updatePump finishes or starts a pending sleep number change at the given time
+func (b *Bed) updatePump(now time.Time) {
+	if b.pumpDone.IsZero() || now.Before(b.pumpStart) {
+		return
+	}
+
+	if now.Before(b.pumpDone) {
+		b.PumpActiveTask = 1
+		return
+	}
+
+	for _, side := range []*BedSide{&b.Left, &b.Right} {
+		if side.targetSleepNumber != 0 {
+			side.SleepNumber = side.targetSleepNumber
+			side.targetSleepNumber = 0
+		}
+	}
+	b.stopPump()
+}
+
+// stopPump abandons any pending sleep number change and leaves the pump idle
+func (b *Bed) stopPump() {
+	b.Left.targetSleepNumber = 0
+	b.Right.targetSleepNumber = 0
+	b.pumpStart = time.Time{}
+	b.pumpDone = time.Time{}
+	b.PumpActiveTask = 0
+}
+
+// ============================================================================
+// HANDLERS
+// ============================================================================
+
+// serveBed handles the /bed endpoints
+func (s *Server) serveBed(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 || path[0] == "" {
+		s.listBeds(w)
+		return
+	}
+
+	if path[0] == "familyStatus" {
+		s.familyStatus(w)
+		return
+	}
+
+	bed := s.bed(path[0])
+	if bed == nil {
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Bed not found")
+		return
+	}
+
+	switch r.Method + " " + strings.Join(path[1:], "/") {
+	case "GET pauseMode":
+		writeJSON(w, pauseModeJSON(bed))
+	case "PUT pauseMode":
+		setPauseMode(w, r, bed)
+	case "GET superStatus":
+		writeJSON(w, superStatusJSON(bed))
+	case "GET nodes":
+		writeJSON(w, map[string]interface{}{"bedId": bed.ID, "nodes": []int{1, 2}})
+	case "GET responsiveAir":
+		writeJSON(w, responsiveAirJSON(bed))
+	case "PUT responsiveAir":
+		setResponsiveAir(w, r, bed)
+	case "PUT sleepNumber":
+		setSleepNumber(w, r, bed, s.pumpDelay)
+	case "GET sleepNumberFavorite":
+		writeJSON(w, map[string]interface{}{
+			"sleepNumberFavoriteLeft":  bed.Left.FavoriteSleepNumber,
+			"sleepNumberFavoriteRight": bed.Right.FavoriteSleepNumber,
+		})
+	case "PUT sleepNumberFavorite":
+		setSleepNumberFavorite(w, r, bed)
+	case "PUT pump/forceIdle":
+		bed.stopPump()
+		writeJSON(w, map[string]interface{}{})
+	case "GET foundation/footwarming":
+		writeJSON(w, footWarmingJSON(bed))
+	case "PUT foundation/footwarming":
+		setFootWarming(w, r, bed)
+	case "GET foundation/system":
+		writeJSON(w, systemJSON(bed))
+	case "PUT foundation/system":
+		setSystem(w, r, bed)
+	case "GET foundation/pinch":
+		writeJSON(w, pinchJSON())
+	case "GET foundation/underbedLight":
+		writeJSON(w, map[string]interface{}{"enableAuto": bed.UnderbedLightAuto, "prefSyncState": "Synced"})
+	case "PUT foundation/underbedLight":
+		setUnderbedLight(w, r, bed)
+	case "GET foundation/status":
+		writeJSON(w, foundationStatusJSON(bed))
+	case "PUT foundation/preset":
+		setPreset(w, r, bed)
+	case "PUT foundation/adjustment/micro":
+		setActuatorPosition(w, r, bed)
+	case "PUT foundation/motion":
+		stopMotion(w, r, bed)
+	case "GET foundation/outlet":
+		getOutlet(w, r, bed)
+	case "PUT foundation/outlet":
+		setOutlet(w, r, bed)
+	default:
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Not Found")
+	}
+}
+
+// listBeds lists the beds of the account
+func (s *Server) listBeds(w http.ResponseWriter) {
+	beds := make([]interface{}, 0, len(s.beds))
+	for _, bed := range s.beds {
+		beds = append(beds, map[string]interface{}{
+			"registrationDate":    "2019-01-01T00:00:00Z",
+			"sleeperRightId":      bed.SleeperRightID,
+			"base":                bed.Base,
+			"returnRequestStatus": 0,
+			"size":                bed.Size,
+			"name":                bed.Name,
+			"serial":              "",
+			"isKidsBed":           false,
+			"dualSleep":           bed.DualSleep,
+			"bedId":               bed.ID,
+			"status":              1,
+			"sleeperLeftId":       bed.SleeperLeftID,
+			"version":             "",
+			"accountId":           "1",
+			"timezone":            "US/Central",
+			"generation":          bed.Generation,
+			"model":               bed.Model,
+			"purchaseDate":        "2019-01-01T00:00:00Z",
+			"macAddress":          "64DBA0000000",
+			"sku":                 bed.Sku,
+			"zipcode":             "55401",
+			"reference":           "",
+		})
+	}
+	writeJSON(w, map[string]interface{}{"beds": beds})
+}
+
+// familyStatus returns the occupancy and sleep number of every bed
+func (s *Server) familyStatus(w http.ResponseWriter) {
+	beds := make([]interface{}, 0, len(s.beds))
+	for _, bed := range s.beds {
+		beds = append(beds, map[string]interface{}{
+			"status":    1,
+			"bedId":     bed.ID,
+			"leftSide":  familySideJSON(bed.Left),
+			"rightSide": familySideJSON(bed.Right),
+		})
+	}
+	writeJSON(w, map[string]interface{}{"beds": beds})
+}
+
+func familySideJSON(side BedSide) map[string]interface{} {
+	return map[string]interface{}{
+		"isInBed":              side.InBed,
+		"alertDetailedMessage": side.AlertMessage,
+		"sleepNumber":          side.SleepNumber,
+		"alertId":              side.AlertID,
+		"lastLink":             "00:00:00",
+		"pressure":             side.Pressure,
+	}
+}
+
+func pauseModeJSON(bed *Bed) map[string]interface{} {
+	mode := "off"
+	if bed.PauseMode {
+		mode = "on"
+	}
+	return map[string]interface{}{"accountId": "1", "bedId": bed.ID, "pauseMode": mode}
+}
+
+func setPauseMode(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	switch r.URL.Query().Get("mode") {
+	case "on":
+		bed.PauseMode = true
+	case "off":
+		bed.PauseMode = false
+	default:
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid pause mode")
+		return
+	}
+
+	writeJSON(w, pauseModeJSON(bed))
+}
+
+func superStatusJSON(bed *Bed) map[string]interface{} {
+	outlets := make([]interface{}, 0, len(bed.Outlets))
+	smartOutlets := make([]interface{}, 0, 2)
+	for i, outlet := range bed.Outlets {
+		outlets = append(outlets, map[string]interface{}{"outletId": i + 1, "setting": outletSetting(outlet)})
+		if i < 2 {
+			smartOutlets = append(smartOutlets, map[string]interface{}{"name": outlet.Name, "outletId": i + 1, "setting": outletSetting(outlet)})
+		}
+	}
+
+	return map[string]interface{}{
+		"bedId": bed.ID,
+		"chambers": map[string]interface{}{
+			"leftChamberOccupancy":       nil,
+			"leftChamberRefreshedState":  nil,
+			"leftChamberType":            1,
+			"rightChamberOccupancy":      nil,
+			"rightChamberRefreshedState": nil,
+			"rightChamberType":           1,
+		},
+		"foundation": map[string]interface{}{
+			"fsCurrentPositionPresetLeft":  presetNames[bed.Left.Preset],
+			"fsCurrentPositionPresetRight": presetNames[bed.Right.Preset],
+			"fsType":                       bed.FoundationType,
+			"outlets":                      outlets,
+		},
+		"pump": map[string]interface{}{
+			"activeTask":               bed.PumpActiveTask,
+			"chamberType":              1,
+			"leftSideSleepNumber":      bed.Left.SleepNumber,
+			"rightSideSleepNumber":     bed.Right.SleepNumber,
+			"sleepNumberFavoriteLeft":  bed.Left.FavoriteSleepNumber,
+			"sleepNumberFavoriteRight": bed.Right.FavoriteSleepNumber,
+		},
+		"smartoutlets": smartOutlets,
+	}
+}
+
+func responsiveAirJSON(bed *Bed) map[string]interface{} {
+	return map[string]interface{}{
+		"adjustmentThreshold": bed.AdjustmentThreshold,
+		"inBedTimeout":        bed.InBedTimeout,
+		"leftSideEnabled":     bed.Left.ResponsiveAir,
+		"outOfBedTimeout":     bed.OutOfBedTimeout,
+		"pollFrequency":       bed.PollFrequency,
+		"prefSyncState":       "Synced",
+		"rightSideEnabled":    bed.Right.ResponsiveAir,
+	}
+}
+
+func setResponsiveAir(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		LeftSideEnabled     *bool `json:"leftSideEnabled"`
+		RightSideEnabled    *bool `json:"rightSideEnabled"`
+		InBedTimeout        *int  `json:"inBedTimeout"`
+		OutOfBedTimeout     *int  `json:"outOfBedTimeout"`
+		AdjustmentThreshold *int  `json:"adjustmentThreshold"`
+		PollFrequency       *int  `json:"pollFrequency"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	setBool(&bed.Left.ResponsiveAir, settings.LeftSideEnabled)
+	setBool(&bed.Right.ResponsiveAir, settings.RightSideEnabled)
+	setInt(&bed.InBedTimeout, settings.InBedTimeout)
+	setInt(&bed.OutOfBedTimeout, settings.OutOfBedTimeout)
+	setInt(&bed.AdjustmentThreshold, settings.AdjustmentThreshold)
+	setInt(&bed.PollFrequency, settings.PollFrequency)
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func setSleepNumber(w http.ResponseWriter, r *http.Request, bed *Bed, delay time.Duration) {
+	var settings struct {
+		Side        string `json:"side"`
+		SleepNumber int    `json:"sleepNumber"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	side := bed.side(settings.Side)
+	if side == nil || settings.SleepNumber < 1 || settings.SleepNumber > 100 {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid sleep number setting")
+		return
+	}
+
+	if delay <= 0 {
+		side.SleepNumber = settings.SleepNumber
+		writeJSON(w, map[string]interface{}{})
+		return
+	}
+
+	// The pump starts after the delay and takes as long again to reach the
+	// new sleep number
+	now := time.Now()
+	side.targetSleepNumber = settings.SleepNumber
+	bed.pumpStart = now.Add(delay)
+	bed.pumpDone = now.Add(2 * delay)
+	writeJSON(w, map[string]interface{}{})
+}
+
+func setSleepNumberFavorite(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		Side                string `json:"side"`
+		SleepNumberFavorite int    `json:"sleepNumberFavorite"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	side := bed.side(settings.Side)
+	if side == nil || settings.SleepNumberFavorite < 1 || settings.SleepNumberFavorite > 100 {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid sleep number favorite setting")
+		return
+	}
+
+	side.FavoriteSleepNumber = settings.SleepNumberFavorite
+	writeJSON(w, map[string]interface{}{})
+}
+
+func footWarmingJSON(bed *Bed) map[string]interface{} {
+	return map[string]interface{}{
+		"footWarmingStatusLeft":  bed.Left.FootWarmingTemp,
+		"footWarmingStatusRight": bed.Right.FootWarmingTemp,
+		"footWarmingTimerLeft":   bed.Left.FootWarmingTimer,
+		"footWarmingTimerRight":  bed.Right.FootWarmingTimer,
+	}
+}
+
+func setFootWarming(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		TempLeft   *int `json:"footWarmingTempLeft"`
+		TimerLeft  *int `json:"footWarmingTimerLeft"`
+		TempRight  *int `json:"footWarmingTempRight"`
+		TimerRight *int `json:"footWarmingTimerRight"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	setInt(&bed.Left.FootWarmingTemp, settings.TempLeft)
+	setInt(&bed.Left.FootWarmingTimer, settings.TimerLeft)
+	setInt(&bed.Right.FootWarmingTemp, settings.TempRight)
+	setInt(&bed.Right.FootWarmingTimer, settings.TimerRight)
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func systemJSON(bed *Bed) map[string]interface{} {
+	return map[string]interface{}{
+		"fsBedType":               bed.BedType,
+		"fsBoardFaults":           0,
+		"fsBoardFeatures":         bed.BoardFeatures,
+		"fsBoardHWRevisionCode":   1,
+		"fsBoardStatus":           0,
+		"fsLeftUnderbedLightPWM":  bed.Left.UnderbedLightPWM,
+		"fsRightUnderbedLightPWM": bed.Right.UnderbedLightPWM,
+	}
+}
+
+func setSystem(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		LeftUnderbedLightPWM  *int `json:"leftUnderbedLightPWM"`
+		RightUnderbedLightPWM *int `json:"rightUnderbedLightPWM"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	setInt(&bed.Left.UnderbedLightPWM, settings.LeftUnderbedLightPWM)
+	setInt(&bed.Right.UnderbedLightPWM, settings.RightUnderbedLightPWM)
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func pinchJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"continuousPinchLeftFoot":         false,
+		"continuousPinchLeftHead":         false,
+		"continuousPinchRightFoot":        false,
+		"continuousPinchRightHead":        false,
+		"pinchEventsLeftFoot":             0,
+		"pinchEventsLeftHead":             0,
+		"pinchEventsRightFoot":            0,
+		"pinchEventsRightHead":            0,
+		"pinchSenseDisconnectedLeftFoot":  false,
+		"pinchSenseDisconnectedLeftHead":  false,
+		"pinchSenseDisconnectedRightFoot": false,
+		"pinchSenseDisconnectedRightHead": false,
+	}
+}
+
+func setUnderbedLight(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		EnableAuto bool `json:"enableAuto"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	bed.UnderbedLightAuto = settings.EnableAuto
+	writeJSON(w, map[string]interface{}{})
+}
+
+func foundationStatusJSON(bed *Bed) map[string]interface{} {
+	return map[string]interface{}{
+		"fsCurrentPositionPresetRight":   presetNames[bed.Right.Preset],
+		"fsNeedsHoming":                  false,
+		"fsRightFootPosition":            hexByte(bed.Right.FootPosition),
+		"fsLeftPositionTimerLSB":         hexByte(bed.Left.PresetTimer & 0xff),
+		"fsTimerPositionPresetLeft":      presetNames[bed.Left.TimedPreset],
+		"fsCurrentPositionPresetLeft":    presetNames[bed.Left.Preset],
+		"fsLeftPositionTimerMSB":         hexByte(bed.Left.PresetTimer >> 8),
+		"fsRightFootActuatorMotorStatus": hexByte(0),
+		"fsCurrentPositionPreset":        presetNames[bed.Right.Preset],
+		"fsTimerPositionPresetRight":     presetNames[bed.Right.TimedPreset],
+		"fsType":                         bed.FoundationType,
+		"fsOutletsOn":                    bed.Outlets[0].On || bed.Outlets[1].On,
+		"fsLeftHeadPosition":             hexByte(bed.Left.HeadPosition),
+		"fsIsMoving":                     bed.IsMoving,
+		"fsRightHeadActuatorMotorStatus": hexByte(0),
+		"fsStatusSummary":                hexByte(0),
+		"fsTimerPositionPreset":          presetNames[bed.Right.TimedPreset],
+		"fsLeftFootPosition":             hexByte(bed.Left.FootPosition),
+		"fsRightPositionTimerLSB":        hexByte(bed.Right.PresetTimer & 0xff),
+		"fsTimedOutletsOn":               false,
+		"fsRightHeadPosition":            hexByte(bed.Right.HeadPosition),
+		"fsConfigured":                   true,
+		"fsRightPositionTimerMSB":        hexByte(bed.Right.PresetTimer >> 8),
+		"fsLeftHeadActuatorMotorStatus":  hexByte(0),
+		"fsLeftFootActuatorMotorStatus":  hexByte(0),
+	}
+}
+
+func setPreset(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		Speed  int    `json:"speed"`
+		Side   string `json:"side"`
+		Preset int    `json:"preset"`
+		Timer  int    `json:"timer"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	side := bed.side(settings.Side)
+	position, ok := presetPositions[settings.Preset]
+	if side == nil || !ok {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid preset")
+		return
+	}
+
+	side.Preset = settings.Preset
+	side.HeadPosition = position[0]
+	side.FootPosition = position[1]
+
+	// A timer in minutes returns the side to flat once it expires
+	side.TimedPreset = presetNone
+	side.PresetTimer = 0
+	if settings.Timer > 0 {
+		side.TimedPreset = settings.Preset
+		side.PresetTimer = settings.Timer * 60
+	}
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func setActuatorPosition(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		Position int    `json:"position"`
+		Side     string `json:"side"`
+		Actuator string `json:"actuator"`
+		Speed    int    `json:"speed"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	side := bed.side(settings.Side)
+	if side == nil || settings.Position < 0 || settings.Position > 100 {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid actuator position")
+		return
+	}
+
+	switch settings.Actuator {
+	case "H":
+		side.HeadPosition = settings.Position
+	case "F":
+		side.FootPosition = settings.Position
+	default:
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid actuator")
+		return
+	}
+	side.Preset = presetNone
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func stopMotion(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		Side string `json:"side"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	if bed.side(settings.Side) == nil {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid side")
+		return
+	}
+
+	bed.IsMoving = false
+	writeJSON(w, map[string]interface{}{})
+}
+
+func getOutlet(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	outletID, err := strconv.Atoi(r.URL.Query().Get("outletId"))
+	if err != nil || outletID < 1 || outletID > len(bed.Outlets) {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid outlet")
+		return
+	}
+
+	outlet := bed.Outlets[outletID-1]
+	var timer interface{}
+	if outlet.Timer > 0 {
+		timer = outlet.Timer
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"bedId":   bed.ID,
+		"outlet":  outletID,
+		"setting": outletSetting(outlet),
+		"timer":   timer,
+	})
+}
+
+func setOutlet(w http.ResponseWriter, r *http.Request, bed *Bed) {
+	var settings struct {
+		OutletID int             `json:"outletId"`
+		Setting  json.RawMessage `json:"setting"`
+		Timer    int             `json:"timer"`
+	}
+	if !readJSON(w, r, &settings) {
+		return
+	}
+
+	// The setting is sent either as a number or as a string
+	setting, err := strconv.Atoi(strings.Trim(string(settings.Setting), `"`))
+	if err != nil || settings.OutletID < 1 || settings.OutletID > len(bed.Outlets) {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid outlet setting")
+		return
+	}
+
+	outlet := &bed.Outlets[settings.OutletID-1]
+	outlet.On = setting != 0
+	outlet.Timer = settings.Timer
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+// ============================================================================
+// SUPPORTING FUNCTIONS
+// ============================================================================
+
+// outletSetting returns the service representation of the outlet state
+func outletSetting(outlet Outlet) int {
+	if outlet.On {
+		return 1
+	}
+	return 0
+}
+
+// hexByte formats a value the way the foundation reports it
+func hexByte(value int) string {
+	return fmt.Sprintf("0x%02x", value)
+}
+
+// setInt updates the target if a value was provided
+func setInt(target *int, value *int) {
+	if value != nil {
+		*target = *value
+	}
+}
+
+// setBool updates the target if a value was provided
+func setBool(target *bool, value *bool) {
+	if value != nil {
+		*target = *value
+	}
+}
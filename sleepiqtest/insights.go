@@ -0,0 +1,144 @@
+package sleepiqtest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ============================================================================
+// HANDLERS
+// ============================================================================
+
+// serveInsights handles requests to the SleepIQ Insights service
+func (s *Server) serveInsights(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 1 && path[0] == "accesstoken" {
+		s.insightsLogin(w, r)
+		return
+	}
+
+	if s.insightsToken == "" || r.URL.Query().Get("access_token") != s.insightsToken {
+		writeError(w, http.StatusUnauthorized, http.StatusUnauthorized, "Access token is invalid")
+		return
+	}
+
+	switch {
+	case path[0] == "activities":
+		s.activities(w, r)
+	case path[0] == "providers":
+		writeJSON(w, providersJSON())
+	case len(path) == 4 && path[0] == "insights" && path[1] == "historical":
+		s.historical(w, path[2], path[3])
+	default:
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Not Found")
+	}
+}
+
+// insightsLogin authenticates against the Insights service and issues a new
+// access token
+func (s *Server) insightsLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	if !s.validCredentials(w, r) {
+		return
+	}
+
+	s.tokens++
+	s.insightsToken = fmt.Sprintf("token-%d", s.tokens)
+
+	writeJSON(w, map[string]interface{}{
+		"token":     s.insightsToken,
+		"sleeperId": s.sleepers[0].ID,
+	})
+}
+
+// activities returns the activities of a sleeper reported by partner devices
+func (s *Server) activities(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	sleeper := s.sleeper(query.Get("sleeperId"))
+	if sleeper == nil {
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Sleeper not found")
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"activities": []interface{}{
+			map[string]interface{}{
+				"sleeperId":    sleeper.ID,
+				"activityDate": query.Get("startDate"),
+				"partner": map[string]interface{}{
+					"nest":  map[string]interface{}{"summary_data": "", "status": nil},
+					"apple": map[string]interface{}{"summary_data": nil, "goal_steps": nil, "daily_steps": nil, "status": nil},
+				},
+			},
+		},
+		"statuses": map[string]interface{}{
+			"fitbit":      false,
+			"underarmour": false,
+			"nest":        false,
+			"withings":    false,
+			"health":      false,
+			"apple":       false,
+			"honeywell":   false,
+			"google":      false,
+		},
+	})
+}
+
+// historical returns the monthly insights of a sleeper or of comparable
+// sleepers. The kind is one of likeme, nearme or sleeper.
+func (s *Server) historical(w http.ResponseWriter, kind string, sleeperID string) {
+	if s.sleeper(sleeperID) == nil {
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Sleeper not found")
+		return
+	}
+
+	entry := map[string]interface{}{
+		"count":       30,
+		"date":        "2019-01",
+		"siqScore":    75,
+		"sleepNumber": 50,
+		"timeInBed":   28800,
+	}
+
+	switch kind {
+	case "likeme", "nearme":
+	case "sleeper":
+		entry["maxScore"] = 90
+		entry["maxScoreDate"] = "2019-01-15"
+		entry["maxTimeInBed"] = 32400
+		entry["maxTimeInBedDate"] = "2019-01-20"
+		entry["totalTimeInBed"] = 864000
+	default:
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"data": []interface{}{entry}})
+}
+
+// providersJSON lists the partner devices supported by the Insights service
+func providersJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"providers": []interface{}{
+			map[string]interface{}{
+				"id":          "nest",
+				"name":        "Nest",
+				"description": "Nest Learning Thermostat",
+				"image":       "",
+				"scope":       []string{},
+				"platforms":   []string{"ios", "android"},
+				"data_types":  []string{"temperature"},
+				"connected":   false,
+				"order":       1,
+				"connectedAt": "2019-01-01T00:00:00Z",
+				"last_sync":   nil,
+				"is_valid":    nil,
+				"permissions": []interface{}{},
+			},
+		},
+	}
+}
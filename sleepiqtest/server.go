@@ -0,0 +1,283 @@
+// Package sleepiqtest provides an in-memory SleepIQ service for tests and
+// demos. The server emulates the SleepIQ REST and Insights APIs with fake
+// beds whose state is changed by control requests, so that a status read
+// after a control call reflects the change.
+//
+//	server := sleepiqtest.NewServer()
+//	defer server.Close()
+//
+//	siq := sleepiq.New(
+//		sleepiq.WithBaseURL(server.BaseURL()),
+//		sleepiq.WithInsightsBaseURL(server.InsightsBaseURL()),
+//	)
+//	siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+package sleepiqtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials accepted by a new server
+const (
+	DefaultUsername = "sleeper@example.com"
+	DefaultPassword = "password"
+)
+
+// Path prefixes of the emulated services
+const (
+	restPrefix     = "/rest"
+	insightsPrefix = "/insights"
+)
+
+// Service error codes returned by the server
+const (
+	errorCodeSessionInvalid = 50002
+)
+
+// Server is a fake SleepIQ service. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	username      string
+	password      string
+	loginKey      string
+	insightsToken string
+	logins        int
+	tokens        int
+	pumpDelay     time.Duration
+	beds          []*Bed
+	sleepers      []Sleeper
+}
+
+// NewServer starts a server with an account holding a single bed with a
+// sleeper on each side. The caller must call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		username: DefaultUsername,
+		password: DefaultPassword,
+		beds:     []*Bed{newBed(DefaultBedID, DefaultLeftSleeperID, DefaultRightSleeperID)},
+		sleepers: []Sleeper{
+			{ID: DefaultLeftSleeperID, FirstName: "Alex", BedID: DefaultBedID, Side: 0, SleepGoal: 480},
+			{ID: DefaultRightSleeperID, FirstName: "Sam", BedID: DefaultBedID, Side: 1, SleepGoal: 450},
+		},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURL returns the base URL of the emulated SleepIQ REST service
+func (s *Server) BaseURL() string {
+	return s.URL + restPrefix
+}
+
+// InsightsBaseURL returns the base URL of the emulated Insights service
+func (s *Server) InsightsBaseURL() string {
+	return s.URL + insightsPrefix
+}
+
+// SetCredentials changes the username and password accepted by the server
+func (s *Server) SetCredentials(username string, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username = username
+	s.password = password
+}
+
+// ExpireSession invalidates the current login key and insights token so that
+// the next request is rejected as if the session had expired
+func (s *Server) ExpireSession() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loginKey = ""
+	s.insightsToken = ""
+}
+
+// SetPumpDelay makes the pump of every bed wait for the delay before it
+// starts a sleep number change and then take as long again to finish it. The
+// pump reports an active task while it works. With no delay, which is the
+// default, a change takes effect at once.
+func (s *Server) SetPumpDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pumpDelay = delay
+}
+
+// Logins returns the number of successful logins to the REST service
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Bed returns a copy of the state of the bed with the given ID
+func (s *Server) Bed(bedID string) (Bed, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updatePumps()
+
+	bed := s.bed(bedID)
+	if bed == nil {
+		return Bed{}, false
+	}
+	return *bed, true
+}
+
+// UpdateBed changes the state of the bed with the given ID, for example to
+// simulate a sleeper getting into bed. It reports whether the bed exists.
+func (s *Server) UpdateBed(bedID string, update func(bed *Bed)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updatePumps()
+
+	bed := s.bed(bedID)
+	if bed == nil {
+		return false
+	}
+	update(bed)
+	return true
+}
+
+// updatePumps moves the pending sleep number changes of the beds forward to
+// the current time. The caller must hold the lock.
+func (s *Server) updatePumps() {
+	now := time.Now()
+	for _, bed := range s.beds {
+		bed.updatePump(now)
+	}
+}
+
+// bed returns the bed with the given ID. The caller must hold the lock.
+func (s *Server) bed(bedID string) *Bed {
+	for _, bed := range s.beds {
+		if bed.ID == bedID {
+			return bed
+		}
+	}
+	return nil
+}
+
+// serveHTTP dispatches requests to the emulated services
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updatePumps()
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, restPrefix+"/"):
+		s.serveREST(w, r, splitPath(strings.TrimPrefix(r.URL.Path, restPrefix)))
+	case strings.HasPrefix(r.URL.Path, insightsPrefix+"/"):
+		s.serveInsights(w, r, splitPath(strings.TrimPrefix(r.URL.Path, insightsPrefix)))
+	default:
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Not Found")
+	}
+}
+
+// serveREST handles requests to the SleepIQ REST service
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 1 && path[0] == "login" {
+		s.login(w, r)
+		return
+	}
+
+	if s.loginKey == "" || r.URL.Query().Get("_k") != s.loginKey {
+		writeError(w, http.StatusUnauthorized, errorCodeSessionInvalid, "Session is invalid")
+		return
+	}
+
+	switch path[0] {
+	case "bed":
+		s.serveBed(w, r, path[1:])
+	case "sleeper":
+		s.serveSleeper(w, r, path[1:])
+	case "sleepData":
+		s.serveSleepData(w, r, path[1:])
+	case "sleepSliceData":
+		s.sleepSliceData(w, r)
+	default:
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Not Found")
+	}
+}
+
+// login authenticates against the REST service and issues a new login key
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	if !s.validCredentials(w, r) {
+		return
+	}
+
+	s.logins++
+	s.loginKey = fmt.Sprintf("key-%d", s.logins)
+
+	http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: fmt.Sprintf("session-%d", s.logins), Path: "/"})
+	writeJSON(w, map[string]interface{}{
+		"userId":            "1",
+		"key":               s.loginKey,
+		"registrationState": 13,
+		"edpLoginStatus":    200,
+		"edpLoginMessage":   "not used",
+	})
+}
+
+// validCredentials checks the credentials in the request body and writes an
+// error response if they are not accepted
+func (s *Server) validCredentials(w http.ResponseWriter, r *http.Request) bool {
+	var creds struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+
+	if !readJSON(w, r, &creds) {
+		return false
+	}
+
+	if creds.Login != s.username || creds.Password != s.password {
+		writeError(w, http.StatusUnauthorized, http.StatusUnauthorized, "Incorrect username or password")
+		return false
+	}
+
+	return true
+}
+
+// ============================================================================
+// SUPPORTING FUNCTIONS
+// ============================================================================
+
+// splitPath splits a url path into its segments
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// readJSON decodes the request body and writes an error response if it is invalid
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid request body - "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeJSON writes the value as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the format used by the SleepIQ service
+func writeError(w http.ResponseWriter, statusCode int, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"Error": map[string]interface{}{
+			"Code":    code,
+			"Message": message,
+		},
+	})
+}
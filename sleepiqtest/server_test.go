@@ -0,0 +1,116 @@
+package sleepiqtest_test
+
+import (
+	"testing"
+	"time"
+
+	sleepiq "github.com/danpenn/SleepIQ"
+	"github.com/danpenn/SleepIQ/sleepiqtest"
+)
+
+func newClient(t *testing.T, server *sleepiqtest.Server) *sleepiq.SleepIQ {
+	siq := sleepiq.New(sleepiq.WithBaseURL(server.BaseURL()), sleepiq.WithInsightsBaseURL(server.InsightsBaseURL()))
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	return siq
+}
+
+func TestServerControlUpdatesStatus(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+	siq := newClient(t, server)
+
+	err := siq.ControlSleepNumber(sleepiqtest.DefaultBedID, sleepiq.Left, 65)
+	if err != nil {
+		t.Fatalf("could not set sleep number - %s", err)
+	}
+
+	familyStatus, err := siq.BedFamilyStatus()
+	if err != nil {
+		t.Fatalf("could not get bed family status - %s", err)
+	}
+
+	if familyStatus.Beds[0].Side(sleepiq.Left).SleepNumber != 65 {
+		t.Errorf("sleep number was not updated. Expected=%d, Actual=%d", 65, familyStatus.Beds[0].Side(sleepiq.Left).SleepNumber)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.SleepNumber != 65 {
+		t.Errorf("bed state was not updated. Expected=%d, Actual=%d", 65, bed.Left.SleepNumber)
+	}
+}
+
+func TestServerUpdateBed(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+	siq := newClient(t, server)
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.Right.InBed = true
+	})
+
+	familyStatus, err := siq.BedFamilyStatus()
+	if err != nil {
+		t.Fatalf("could not get bed family status - %s", err)
+	}
+
+	if !familyStatus.Beds[0].Side(sleepiq.Right).IsInBed {
+		t.Error("sleeper should be in bed")
+	}
+}
+
+func TestServerExpireSession(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+	siq := newClient(t, server)
+
+	server.ExpireSession()
+
+	_, err := siq.Beds()
+	if err != nil {
+		t.Fatalf("could not get beds after the session expired - %s", err)
+	}
+
+	if server.Logins() != 2 {
+		t.Errorf("unexpected number of logins. Expected=%d, Actual=%d", 2, server.Logins())
+	}
+}
+
+func TestServerPumpDelay(t *testing.T) {
+	server := sleepiqtest.NewServer()
+	defer server.Close()
+	siq := newClient(t, server)
+
+	server.SetPumpDelay(time.Hour)
+
+	err := siq.ControlSleepNumber(sleepiqtest.DefaultBedID, sleepiq.Right, 65)
+	if err != nil {
+		t.Fatalf("could not set sleep number - %s", err)
+	}
+
+	bed, _ := server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Right.SleepNumber != 50 || bed.PumpActiveTask != 0 {
+		t.Errorf("sleep number changed before the pump started. Actual=%d, ActiveTask=%d", bed.Right.SleepNumber, bed.PumpActiveTask)
+	}
+
+	// Forcing the pump idle abandons the change
+	err = siq.ControlPumpForceIdle(sleepiqtest.DefaultBedID)
+	if err != nil {
+		t.Fatalf("could not force the pump idle - %s", err)
+	}
+
+	server.SetPumpDelay(0)
+	err = siq.ControlSleepNumber(sleepiqtest.DefaultBedID, sleepiq.Left, 35)
+	if err != nil {
+		t.Fatalf("could not set sleep number - %s", err)
+	}
+
+	bed, _ = server.Bed(sleepiqtest.DefaultBedID)
+	if bed.Left.SleepNumber != 35 || bed.Right.SleepNumber != 50 {
+		t.Errorf("unexpected sleep numbers. Expected=%d/%d, Actual=%d/%d", 35, 50, bed.Left.SleepNumber, bed.Right.SleepNumber)
+	}
+}
@@ -0,0 +1,195 @@
+package sleepiqtest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sleeper is a fake sleeper (person) of the account
+type Sleeper struct {
+	ID        string
+	FirstName string
+	BedID     string
+	Side      int // 0 is the left side and 1 the right side
+	SleepGoal int // Minutes
+}
+
+// ============================================================================
+// HANDLERS
+// ============================================================================
+
+// serveSleeper handles the /sleeper endpoints
+func (s *Server) serveSleeper(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 || path[0] == "" {
+		sleepers := make([]interface{}, 0, len(s.sleepers))
+		for i, sleeper := range s.sleepers {
+			sleepers = append(sleepers, map[string]interface{}{
+				"firstName":      sleeper.FirstName,
+				"active":         true,
+				"emailValidated": true,
+				"isChild":        false,
+				"bedId":          sleeper.BedID,
+				"birthYear":      "1980",
+				"zipCode":        "55401",
+				"timezone":       "US/Central",
+				"isMale":         false,
+				"weight":         150,
+				"duration":       nil,
+				"sleeperId":      sleeper.ID,
+				"height":         68,
+				"licenseVersion": 6,
+				"username":       s.username,
+				"birthMonth":     1,
+				"sleepGoal":      sleeper.SleepGoal,
+				"isAccountOwner": i == 0,
+				"accountId":      "1",
+				"email":          s.username,
+				"avatar":         "",
+				"lastLogin":      "2019-01-01 00:00:00 CST",
+				"side":           sleeper.Side,
+			})
+		}
+		writeJSON(w, map[string]interface{}{"sleepers": sleepers})
+		return
+	}
+
+	if len(path) == 2 && path[1] == "preferences" && s.sleeper(path[0]) != nil {
+		writeJSON(w, map[string]interface{}{
+			"preferences": map[string]interface{}{"notifications": []interface{}{}},
+			"sleeperId":   path[0],
+		})
+		return
+	}
+
+	writeError(w, http.StatusNotFound, http.StatusNotFound, "Not Found")
+}
+
+// serveSleepData handles the /sleepData endpoints
+func (s *Server) serveSleepData(w http.ResponseWriter, r *http.Request, path []string) {
+	query := r.URL.Query()
+
+	switch strings.Join(path, "/") {
+	case "":
+		sleepers := make([]interface{}, 0, len(s.sleepers))
+		for _, sleeper := range s.sleepers {
+			sleepers = append(sleepers, map[string]interface{}{
+				"sleeperId":             sleeper.ID,
+				"message":               "",
+				"tip":                   "",
+				"avgHeartRate":          60,
+				"avgRespirationRate":    14,
+				"totalSleepSessionTime": 28800,
+				"inBed":                 28800,
+				"outOfBed":              600,
+				"restful":               25200,
+				"restless":              3000,
+				"avgSleepIQ":            75,
+				"sleepData": []interface{}{
+					map[string]interface{}{
+						"tip":       "",
+						"message":   "",
+						"date":      query.Get("date"),
+						"sessions":  []interface{}{sleepSessionJSON()},
+						"goalEntry": nil,
+						"tags":      []interface{}{},
+					},
+				},
+			})
+		}
+		writeJSON(w, map[string]interface{}{"sleepers": sleepers})
+
+	case "byMonth":
+		startDate, err := time.Parse("2006-01-02", query.Get("startDate"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, http.StatusBadRequest, "Invalid startDate")
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"monthSleepData": map[string]interface{}{
+				"date":     startDate.Format("2006-01"),
+				"days":     []interface{}{},
+				"sleepers": []interface{}{},
+			},
+		})
+
+	case "editedHidden":
+		sleeper := s.sleeper(query.Get("sleeperId"))
+		if sleeper == nil {
+			writeError(w, http.StatusNotFound, http.StatusNotFound, "Sleeper not found")
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"sleepers": []interface{}{
+				map[string]interface{}{
+					"editedSleepSessions": []interface{}{},
+					"hiddenSleepSessions": []interface{}{},
+					"sleeperId":           sleeper.ID,
+				},
+			},
+		})
+
+	default:
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Not Found")
+	}
+}
+
+// sleepSliceData returns the detailed nightly activity of a sleeper
+func (s *Server) sleepSliceData(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	sleeper := s.sleeper(query.Get("sleeper"))
+	if sleeper == nil {
+		writeError(w, http.StatusNotFound, http.StatusNotFound, "Sleeper not found")
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"sleepers": []interface{}{
+			map[string]interface{}{
+				"days": []interface{}{
+					map[string]interface{}{
+						"date": query.Get("date"),
+						"sliceList": []interface{}{
+							map[string]interface{}{"outOfBedTime": 0, "restfulTime": 120, "restlessTime": 24, "type": 3},
+						},
+					},
+				},
+				"sleeperId": sleeper.ID,
+				"sliceSize": 144,
+			},
+		},
+	})
+}
+
+// sleeper returns the sleeper with the given ID. The caller must hold the lock.
+func (s *Server) sleeper(sleeperID string) *Sleeper {
+	for i := range s.sleepers {
+		if s.sleepers[i].ID == sleeperID {
+			return &s.sleepers[i]
+		}
+	}
+	return nil
+}
+
+// sleepSessionJSON returns a night of sleep
+func sleepSessionJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"startDate":             "2019-01-01T22:00:00",
+		"longest":               true,
+		"sleepIQCalculating":    false,
+		"originalStartDate":     "2019-01-01T22:00:00",
+		"restful":               25200,
+		"originalEndDate":       "2019-01-02T06:00:00",
+		"sleepNumber":           50,
+		"totalSleepSessionTime": 28800,
+		"avgHeartRate":          60,
+		"restless":              3000,
+		"avgRespirationRate":    14,
+		"isFinalized":           true,
+		"sleepQuotient":         75,
+		"endDate":               "2019-01-02T06:00:00",
+		"outOfBed":              600,
+		"inBed":                 28800,
+	}
+}
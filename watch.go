@@ -0,0 +1,270 @@
+package sleepiq
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// EVENTS
+// ============================================================================
+
+// EventType identifies a change reported by Watch
+type EventType int
+
+// Event types
+const (
+	EventInBed              EventType = iota + 1 // A sleeper got into bed
+	EventOutOfBed                                // A sleeper got out of bed
+	EventSleepNumberChanged                      // The sleep number of a side changed
+	EventPressureChanged                         // The pressure of a side changed by at least the pressure threshold
+	EventAlertRaised                             // The bed raised an alert for a side
+	EventError                                   // The bed status could not be retrieved
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case EventInBed:
+		return "InBed"
+	case EventOutOfBed:
+		return "OutOfBed"
+	case EventSleepNumberChanged:
+		return "SleepNumberChanged"
+	case EventPressureChanged:
+		return "PressureChanged"
+	case EventAlertRaised:
+		return "AlertRaised"
+	case EventError:
+		return "Error"
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event describes a change of one side of a bed. Bed holds the status of
+// both sides at the time of the change, for example to check whether both
+// sleepers are in bed. Events of type EventError only set Time and Err.
+type Event struct {
+	Type     EventType
+	BedID    string
+	Side     Side
+	Time     time.Time
+	Previous SideStatus
+	Current  SideStatus
+	Bed      BedStatus
+	Err      error
+}
+
+// ============================================================================
+// WATCH OPTIONS
+// ============================================================================
+
+// WatchOption configures Watch
+type WatchOption func(*watchConfig)
+
+// watchConfig holds the settings of Watch
+type watchConfig struct {
+	debounce          time.Duration
+	pressureThreshold int
+}
+
+// WithDebounce only reports a sleeper getting in or out of bed once the
+// new occupancy has been observed for at least d. Shorter changes, such as
+// a sleeper turning over near the edge of the bed, are ignored.
+func WithDebounce(d time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		c.debounce = d
+	}
+}
+
+// WithPressureThreshold only reports pressure changes of at least threshold.
+// By default every change is reported.
+func WithPressureThreshold(threshold int) WatchOption {
+	return func(c *watchConfig) {
+		c.pressureThreshold = threshold
+	}
+}
+
+// ============================================================================
+// WATCH
+// ============================================================================
+
+// Watch polls BedFamilyStatus at the given interval and reports changes of
+// each side of every bed on the returned channel. The first status is
+// retrieved before Watch returns and is used as the baseline, so a failure
+// to retrieve it is returned as an error. Later failures are reported as
+// events of type EventError and polling continues. The channel is closed
+// once ctx is done.
+func (s *SleepIQ) Watch(ctx context.Context, interval time.Duration, options ...WatchOption) (<-chan Event, error) {
+	if interval <= 0 {
+		return nil, invalidParameter("parameter 'interval' must be greater than zero")
+	}
+
+	var config watchConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	status, err := s.BedFamilyStatusContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newWatcher(config, status)
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			var changes []Event
+			status, err := s.BedFamilyStatusContext(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				changes = []Event{{Type: EventError, Time: time.Now(), Err: err}}
+			} else {
+				changes = w.update(status, time.Now())
+			}
+
+			for _, event := range changes {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// watcher compares bed status snapshots and derives events from them
+type watcher struct {
+	config watchConfig
+	sides  map[watchKey]*watchedSide
+}
+
+// watchKey identifies a side of a bed
+type watchKey struct {
+	bedID string
+	side  Side
+}
+
+// watchedSide holds the last reported status of a side and an occupancy
+// change that is waiting for the debounce time to pass
+type watchedSide struct {
+	reported     SideStatus
+	pending      bool
+	pendingSince time.Time
+}
+
+// newWatcher creates a watcher with the given status as the baseline
+func newWatcher(config watchConfig, status FamilyStatusDetails) *watcher {
+	w := &watcher{
+		config: config,
+		sides:  make(map[watchKey]*watchedSide),
+	}
+
+	for _, bed := range status.Beds {
+		for _, side := range Sides {
+			w.sides[watchKey{bed.BedID, side}] = &watchedSide{reported: bed.Side(side)}
+		}
+	}
+
+	return w
+}
+
+// update compares the status with the last reported status and returns the
+// resulting events
+func (w *watcher) update(status FamilyStatusDetails, now time.Time) []Event {
+	var events []Event
+
+	for _, bed := range status.Beds {
+		for _, side := range Sides {
+			events = append(events, w.updateSide(bed, side, bed.Side(side), now)...)
+		}
+	}
+
+	return events
+}
+
+// updateSide compares the status of one side with the last reported status
+func (w *watcher) updateSide(bed BedStatus, side Side, current SideStatus, now time.Time) []Event {
+	key := watchKey{bed.BedID, side}
+
+	// Beds that were added to the account while watching start a new baseline
+	watched, ok := w.sides[key]
+	if !ok {
+		w.sides[key] = &watchedSide{reported: current}
+		return nil
+	}
+
+	var events []Event
+	event := func(eventType EventType) {
+		events = append(events, Event{
+			Type:     eventType,
+			BedID:    bed.BedID,
+			Side:     side,
+			Time:     now,
+			Previous: watched.reported,
+			Current:  current,
+			Bed:      bed,
+		})
+	}
+
+	// Occupancy is only reported once it has been stable for the debounce time
+	if current.IsInBed == watched.reported.IsInBed {
+		watched.pending = false
+	} else {
+		if !watched.pending {
+			watched.pending = true
+			watched.pendingSince = now
+		}
+
+		if now.Sub(watched.pendingSince) >= w.config.debounce {
+			if current.IsInBed {
+				event(EventInBed)
+			} else {
+				event(EventOutOfBed)
+			}
+			watched.reported.IsInBed = current.IsInBed
+			watched.pending = false
+		}
+	}
+
+	if current.SleepNumber != watched.reported.SleepNumber {
+		event(EventSleepNumberChanged)
+		watched.reported.SleepNumber = current.SleepNumber
+	}
+
+	change := current.Pressure - watched.reported.Pressure
+	if change < 0 {
+		change = -change
+	}
+	if change > 0 && change >= w.config.pressureThreshold {
+		event(EventPressureChanged)
+		watched.reported.Pressure = current.Pressure
+	}
+
+	if current.AlertID != 0 && current.AlertID != watched.reported.AlertID {
+		event(EventAlertRaised)
+	}
+	watched.reported.AlertID = current.AlertID
+	watched.reported.AlertDetailedMessage = current.AlertDetailedMessage
+	watched.reported.LastLink = current.LastLink
+
+	return events
+}
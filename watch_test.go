@@ -0,0 +1,139 @@
+package sleepiq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danpenn/SleepIQ/sleepiqtest"
+)
+
+func familyStatus(left SideStatus, right SideStatus) FamilyStatusDetails {
+	return FamilyStatusDetails{
+		Beds: []BedStatus{{BedID: "bed", Sides: [2]SideStatus{Left: left, Right: right}}},
+	}
+}
+
+func eventTypes(events []Event) []EventType {
+	var types []EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestWatcherEvents(t *testing.T) {
+	start := time.Now()
+	w := newWatcher(watchConfig{}, familyStatus(SideStatus{SleepNumber: 50}, SideStatus{SleepNumber: 50}))
+
+	events := w.update(familyStatus(SideStatus{IsInBed: true, SleepNumber: 50, Pressure: 900}, SideStatus{SleepNumber: 40, AlertID: 3}), start)
+
+	expected := []EventType{EventInBed, EventPressureChanged, EventSleepNumberChanged, EventAlertRaised}
+	actual := eventTypes(events)
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected events. Expected=%v, Actual=%v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("unexpected event. Expected=%s, Actual=%s", expected[i], actual[i])
+		}
+	}
+
+	if events[0].Side != Left || events[2].Side != Right {
+		t.Errorf("unexpected sides. Expected=left/right, Actual=%s/%s", events[0].Side, events[2].Side)
+	}
+
+	if events[2].Previous.SleepNumber != 50 || events[2].Current.SleepNumber != 40 {
+		t.Errorf("unexpected sleep numbers. Expected=50/40, Actual=%d/%d", events[2].Previous.SleepNumber, events[2].Current.SleepNumber)
+	}
+
+	// The same status raises no further events, including the alert
+	events = w.update(familyStatus(SideStatus{IsInBed: true, SleepNumber: 50, Pressure: 900}, SideStatus{SleepNumber: 40, AlertID: 3}), start)
+	if len(events) != 0 {
+		t.Errorf("unexpected events. Expected=none, Actual=%v", eventTypes(events))
+	}
+}
+
+func TestWatcherDebounce(t *testing.T) {
+	start := time.Now()
+	config := watchConfig{debounce: time.Minute}
+	w := newWatcher(config, familyStatus(SideStatus{}, SideStatus{}))
+
+	// A short visit to the bed is ignored
+	events := w.update(familyStatus(SideStatus{IsInBed: true}, SideStatus{}), start)
+	events = append(events, w.update(familyStatus(SideStatus{}, SideStatus{}), start.Add(30*time.Second))...)
+	if len(events) != 0 {
+		t.Errorf("unexpected events. Expected=none, Actual=%v", eventTypes(events))
+	}
+
+	// A stable change is reported once the debounce time has passed
+	events = w.update(familyStatus(SideStatus{IsInBed: true}, SideStatus{}), start.Add(time.Minute))
+	events = append(events, w.update(familyStatus(SideStatus{IsInBed: true}, SideStatus{}), start.Add(90*time.Second))...)
+	if len(events) != 0 {
+		t.Errorf("unexpected events. Expected=none, Actual=%v", eventTypes(events))
+	}
+
+	events = w.update(familyStatus(SideStatus{IsInBed: true}, SideStatus{}), start.Add(2*time.Minute))
+	if len(events) != 1 || events[0].Type != EventInBed {
+		t.Errorf("unexpected events. Expected=[InBed], Actual=%v", eventTypes(events))
+	}
+}
+
+func TestWatcherPressureThreshold(t *testing.T) {
+	w := newWatcher(watchConfig{pressureThreshold: 100}, familyStatus(SideStatus{Pressure: 1000}, SideStatus{}))
+
+	events := w.update(familyStatus(SideStatus{Pressure: 1050}, SideStatus{}), time.Now())
+	if len(events) != 0 {
+		t.Errorf("unexpected events. Expected=none, Actual=%v", eventTypes(events))
+	}
+
+	events = w.update(familyStatus(SideStatus{Pressure: 1100}, SideStatus{}), time.Now())
+	if len(events) != 1 || events[0].Type != EventPressureChanged {
+		t.Errorf("unexpected events. Expected=[PressureChanged], Actual=%v", eventTypes(events))
+	}
+}
+
+func TestWatch(t *testing.T) {
+	server, siq := newTestServer()
+	defer server.Close()
+
+	_, err := siq.Login(sleepiqtest.DefaultUsername, sleepiqtest.DefaultPassword)
+	if err != nil {
+		t.Fatalf("login failed - expected success. %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := siq.Watch(ctx, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("could not watch the bed - %s", err)
+	}
+
+	server.UpdateBed(sleepiqtest.DefaultBedID, func(bed *sleepiqtest.Bed) {
+		bed.Left.InBed = true
+	})
+
+	event := <-events
+	if event.Type != EventInBed || event.BedID != sleepiqtest.DefaultBedID || event.Side != Left {
+		t.Errorf("unexpected event. Expected=InBed/%s/left, Actual=%s/%s/%s", sleepiqtest.DefaultBedID, event.Type, event.BedID, event.Side)
+	}
+
+	if !event.Bed.Side(Left).IsInBed || event.Bed.Side(Right).IsInBed {
+		t.Error("event should hold the status of both sides")
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchNotLoggedIn(t *testing.T) {
+	siq := New()
+
+	_, err := siq.Watch(context.Background(), time.Second)
+	if !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("unexpected error. Expected=%v, Actual=%v", ErrNotLoggedIn, err)
+	}
+}